@@ -6,9 +6,12 @@ import (
 	"go-gin-boilerplate/config"
 	"go-gin-boilerplate/internal/cache"
 	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
 	"go-gin-boilerplate/internal/handler"
 	"go-gin-boilerplate/internal/handler/api"
+	"go-gin-boilerplate/internal/mailer"
 	"go-gin-boilerplate/internal/middleware"
+	"go-gin-boilerplate/internal/port"
 	"go-gin-boilerplate/internal/repository"
 	"go-gin-boilerplate/internal/service"
 	"go-gin-boilerplate/internal/utils"
@@ -46,6 +49,11 @@ import (
 // @name Authorization
 // @description Enter JWT token in the format: Bearer {your_token_here}
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key created through /api-keys
+
 func gracefulShutdown(server *http.Server) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -63,6 +71,26 @@ func gracefulShutdown(server *http.Server) {
 	log.Println("Server gracefully stopped")
 }
 
+// reloadKeysOnSignal re-reads the config on SIGHUP and swaps in the JWT key set,
+// so signing keys can be rotated without restarting the server
+func reloadKeysOnSignal(env string, jwt *utils.JWT) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	for range hup {
+		appConfig, err := config.LoadConfigFromEnv(env)
+		if err != nil {
+			log.Printf("Failed to reload config: %v", err)
+			continue
+		}
+		if err := jwt.ReloadKeys(&appConfig.JWT); err != nil {
+			log.Printf("Failed to reload JWT keys: %v", err)
+			continue
+		}
+		log.Println("JWT keys reloaded")
+	}
+}
+
 func main() {
 	// Load configuration
 	env := os.Getenv("APP_ENV")
@@ -78,17 +106,34 @@ func main() {
 	// Print configuration
 	appConfig.PrintConfig()
 
-	_ = cache.InitRedis(&appConfig.Redis)
+	redisClient := cache.InitRedis(&appConfig.Redis)
 
+	// Repositories join the transaction of a unit of work through the context
 	var baseRepo db.BaseRepository
+	var unitOfWork port.UnitOfWork
 	switch appConfig.Database.Type {
 	case "postgresql", "postgres":
-		baseRepo = db.NewPgsqlRepository(db.InitPgsql(&appConfig.Database))
+		gormDB := db.InitPgsql(&appConfig.Database)
+		baseRepo = db.NewPgsqlRepository(gormDB)
+		unitOfWork = db.NewPgsqlUnitOfWork(gormDB)
 	case "mongodb", "mongo":
-		baseRepo = db.NewMongoRepository(db.InitMongo(&appConfig.Database), appConfig.Database.MongoDB.DBName)
+		mongoClient := db.InitMongo(&appConfig.Database)
+		baseRepo = db.NewMongoRepository(mongoClient, appConfig.Database.MongoDB.DBName)
+		unitOfWork, err = db.NewMongoUnitOfWork(mongoClient, appConfig.Database.MongoDB.AllowNonTransactional)
+		if err != nil {
+			log.Fatalf("Failed to initialize MongoDB unit of work: %v", err)
+		}
 	}
 
-	router := gin.Default()
+	// Errors recorded by handlers and panics become problem responses; their
+	// details are kept from clients in production
+	router := gin.New()
+	router.Use(gin.Logger(), middleware.ErrorHandler(appConfig.IsProduction()))
+	// Client IPs key login throttling and sessions, so forwarded headers are
+	// only believed from configured proxies
+	if err := router.SetTrustedProxies(appConfig.Server.TrustedProxies); err != nil {
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
 
 	// Swagger route
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -104,24 +149,107 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
-	jwt := utils.NewJWT(&appConfig.JWT)
+	jwt, err := utils.NewJWT(&appConfig.JWT)
+	if err != nil {
+		log.Fatalf("Failed to initialize JWT: %v", err)
+	}
+	go reloadKeysOnSignal(env, jwt)
+
+	// Public keys for services that verify our tokens independently
+	router.GET("/.well-known/jwks.json", handler.NewJWKSHandler(jwt).GetJWKS)
+
+	userRepo := repository.NewUserRepository(baseRepo, "user")
+	refreshStore := cache.NewRefreshTokenStore(redisClient, appConfig.JWT.RefreshDuration)
+	revocationStore := cache.NewRevocationStore(redisClient, appConfig.JWT.RefreshDuration)
+	sessionStore := cache.NewSessionStore(redisClient)
+	apiKeyRepo := repository.NewAPIKeyRepository(baseRepo, "api_key")
+	authSvc := service.NewAuthService(jwt, userRepo, apiKeyRepo, refreshStore, revocationStore, sessionStore)
+	apiKeySvc := service.NewAPIKeyService(apiKeyRepo, userRepo)
+	authMiddleware := middleware.AuthMiddleware(jwt, revocationStore, apiKeySvc)
+	authorizer := service.NewPolicyAuthorizer(appConfig.Authz.DenyByDefault)
+	service.RegisterDefaultPolicies(authorizer)
 
 	apiRouter := router.Group("/api/v1")
 
 	// initialize auth router
 	authRouter := apiRouter.Group("/auth")
+	authRouter.Use(middleware.ClientInfo())
 	{
-		authSvc := service.NewAuthService(jwt)
-		authHandler := handler.NewAuthHandler(authSvc)
+		accountSvc := service.NewAccountService(jwt, userRepo, apiKeyRepo, revocationStore, mailer.NewMailer(&appConfig.Mail), appConfig.Mail.LinkBaseURL)
+		loginAttemptStore := cache.NewLoginAttemptStore(redisClient)
+		if appConfig.LoginThrottle.Store == "memory" {
+			loginAttemptStore = cache.NewMemoryLoginAttemptStore()
+		}
+		loginThrottler := service.NewLoginThrottler(loginAttemptStore, domain.LoginThrottlePolicy{
+			MaxAttempts:     appConfig.LoginThrottle.MaxAttempts,
+			IPMaxAttempts:   appConfig.LoginThrottle.IPMaxAttempts,
+			Window:          appConfig.LoginThrottle.Window,
+			LockoutDuration: appConfig.LoginThrottle.LockoutDuration,
+			BaseDelay:       appConfig.LoginThrottle.BaseDelay,
+			MaxDelay:        appConfig.LoginThrottle.MaxDelay,
+		})
+		authHandler := handler.NewAuthHandler(authSvc, accountSvc, loginThrottler)
 		authRouter.POST("/login", authHandler.Login)
+		authRouter.POST("/register", authHandler.Register)
+		authRouter.POST("/refresh", authHandler.RefreshToken)
+		authRouter.POST("/logout", authHandler.Logout)
+		authRouter.POST("/password/forgot", authHandler.ForgotPassword)
+		authRouter.POST("/password/reset", authHandler.ResetPassword)
+		authRouter.POST("/verify", authHandler.VerifyEmail)
+		authRouter.POST("/verify/resend", authMiddleware, authHandler.ResendVerification)
+
+		oidcProviders := make(map[string]*utils.OIDCProvider, len(appConfig.OIDC.Providers))
+		for name, providerConfig := range appConfig.OIDC.Providers {
+			oidcProviders[name] = utils.NewOIDCProvider(providerConfig)
+		}
+		oidcStateStore := cache.NewOIDCStateStore(redisClient, appConfig.OIDC.StateTTL)
+		identityRepo := repository.NewExternalIdentityRepository(baseRepo, "external_identity")
+		oidcSvc := service.NewOIDCService(oidcProviders, oidcStateStore, identityRepo, userRepo, authSvc, unitOfWork)
+		oidcHandler := handler.NewOIDCHandler(oidcSvc)
+		authRouter.GET("/oidc/:provider", oidcHandler.Login)
+		authRouter.GET("/oidc/:provider/callback", oidcHandler.Callback)
+
+		sessionRouter := authRouter.Group("/sessions")
+		sessionRouter.Use(authMiddleware)
+		sessionSvc := service.NewSessionService(sessionStore, refreshStore, revocationStore)
+		api.RegisterSessionRoutes(sessionRouter, handler.NewSessionHandler(sessionSvc))
+
+		mfaSvc := service.NewMFAService(jwt, userRepo, revocationStore, authSvc, loginThrottler, appConfig.MFA.Issuer)
+		api.RegisterMFARoutes(authRouter.Group("/mfa"), handler.NewMFAHandler(mfaSvc), authMiddleware)
+	}
+
+	// initialize OAuth2 authorization server router
+	oauthRouter := apiRouter.Group("/oauth")
+	{
+		oauthClientRepo := repository.NewOAuthClientRepository(baseRepo, "oauth_client")
+		codeStore := cache.NewAuthorizationCodeStore(redisClient, appConfig.OAuth.CodeTTL)
+		oauthSvc := service.NewOAuthService(jwt, oauthClientRepo, codeStore, userRepo, revocationStore)
+		oauthHandler := handler.NewOAuthHandler(oauthSvc)
+		api.RegisterOAuthRoutes(oauthRouter, oauthHandler, authMiddleware)
+	}
+
+	// initialize API key router for the current user
+	apiKeyRouter := apiRouter.Group("/api-keys")
+	apiKeyRouter.Use(authMiddleware)
+	{
+		apiKeyHandler := handler.NewAPIKeyHandler(apiKeySvc)
+		api.RegisterAPIKeyRoutes(apiKeyRouter, apiKeyHandler)
+	}
+
+	// initialize admin router, restricted to administrators
+	adminRouter := apiRouter.Group("/admin")
+	adminRouter.Use(authMiddleware, middleware.RequirePermission(domain.PermissionUsersManage))
+	{
+		adminHandler := handler.NewAdminHandler(authSvc)
+		api.RegisterAdminRoutes(adminRouter, adminHandler)
 	}
 
 	// initialize bar router with auth middleware
 	barRouter := apiRouter.Group("/bar")
-	barRouter.Use(middleware.AuthMiddleware(jwt))
+	barRouter.Use(authMiddleware)
 	{
 		barRepo := repository.NewBarRepository(baseRepo, "bar")
-		barSvc := service.NewBarService(barRepo)
+		barSvc := service.NewBarService(barRepo, authorizer)
 		barHandler := handler.NewBarHandler(barSvc)
 		api.RegisterBarRoutes(barRouter, barHandler)
 	}
@@ -130,9 +258,9 @@ func main() {
 	fooRouter := apiRouter.Group("/foo")
 	{
 		fooRepo := repository.NewFooRepository(baseRepo, "foo")
-		fooSvc := service.NewFooService(fooRepo)
+		fooSvc := service.NewFooService(fooRepo, authorizer)
 		fooHandler := handler.NewFooHandler(fooSvc)
-		api.RegisterFooRoutes(fooRouter, fooHandler)
+		api.RegisterFooRoutes(fooRouter, fooHandler, authMiddleware)
 	}
 
 	Port := appConfig.Server.Port
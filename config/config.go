@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,6 +16,13 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
 	Redis    RedisConfig    `mapstructure:"redis"`
+	Authz    AuthzConfig    `mapstructure:"authz"`
+	OIDC     OIDCConfig     `mapstructure:"oidc"`
+	OAuth    OAuthConfig    `mapstructure:"oauth"`
+	MFA      MFAConfig      `mapstructure:"mfa"`
+	Mail     MailConfig     `mapstructure:"mail"`
+	// LoginThrottle configures brute-force protection for /auth/login
+	LoginThrottle LoginThrottleConfig `mapstructure:"login_throttle"`
 }
 
 // AppConfig represents application-level configuration
@@ -25,6 +33,10 @@ type AppConfig struct {
 // ServerConfig represents server configuration
 type ServerConfig struct {
 	Port string `mapstructure:"port"`
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-For
+	// header is believed; by default none are and the client IP is the
+	// connection's remote address
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig represents database configuration
@@ -53,13 +65,35 @@ type MongoDBConfig struct {
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
+	// AllowNonTransactional starts on a standalone server, where units of
+	// work run without transactions and are not atomic
+	AllowNonTransactional bool `mapstructure:"allow_non_transactional"`
 }
 
 // JWTConfig represents JWT configuration
 type JWTConfig struct {
-	Secret          string        `mapstructure:"secret"`
-	AccessDuration  time.Duration `mapstructure:"access_duration"`
-	RefreshDuration time.Duration `mapstructure:"refresh_duration"`
+	Algorithm       string         `mapstructure:"algorithm"`
+	Secret          string         `mapstructure:"secret"`
+	PrivateKey      string         `mapstructure:"private_key"`
+	PrivateKeyFile  string         `mapstructure:"private_key_file"`
+	Keys            []JWTKeyConfig `mapstructure:"keys"`
+	ActiveKid       string         `mapstructure:"active_kid"`
+	AccessDuration  time.Duration  `mapstructure:"access_duration"`
+	RefreshDuration time.Duration  `mapstructure:"refresh_duration"`
+	Issuer          string         `mapstructure:"issuer"`
+	Audience        string         `mapstructure:"audience"`
+}
+
+// JWTKeyConfig represents one key of a rotating JWT key set. Keys without a
+// private key (or secret) can only verify tokens.
+type JWTKeyConfig struct {
+	Kid            string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"`
+	Secret         string `mapstructure:"secret"`
+	PrivateKey     string `mapstructure:"private_key"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKey      string `mapstructure:"public_key"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
 }
 
 // RedisConfig represents Redis configuration
@@ -69,6 +103,78 @@ type RedisConfig struct {
 	Password string `mapstructure:"password"`
 }
 
+// AuthzConfig represents authorization policy configuration
+type AuthzConfig struct {
+	// DenyByDefault denies actions that have no declared policy
+	DenyByDefault bool `mapstructure:"deny_by_default"`
+}
+
+// OIDCConfig represents external OpenID Connect identity providers
+type OIDCConfig struct {
+	// StateTTL bounds how long a started login can wait for its callback
+	StateTTL  time.Duration                 `mapstructure:"state_ttl"`
+	Providers map[string]OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig represents one OpenID Connect provider. The provider's
+// endpoints are discovered from its issuer URL.
+type OIDCProviderConfig struct {
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// OAuthConfig represents the OAuth2 authorization server configuration
+type OAuthConfig struct {
+	// CodeTTL bounds how long an authorization code can wait to be redeemed
+	CodeTTL time.Duration `mapstructure:"code_ttl"`
+}
+
+// MFAConfig represents multi-factor authentication configuration
+type MFAConfig struct {
+	// Issuer is the account issuer shown in authenticator apps
+	Issuer string `mapstructure:"issuer"`
+}
+
+// MailConfig represents outgoing email configuration
+type MailConfig struct {
+	// Driver is "smtp" to deliver mail or "log" to write it to Dir (or the
+	// log when Dir is empty) for development and tests; production requires smtp
+	Driver string `mapstructure:"driver"`
+	From   string `mapstructure:"from"`
+	Dir    string `mapstructure:"dir"`
+	// LinkBaseURL is the frontend URL that reset and verification links point to
+	LinkBaseURL string     `mapstructure:"link_base_url"`
+	SMTP        SMTPConfig `mapstructure:"smtp"`
+}
+
+// SMTPConfig represents SMTP server configuration
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     string `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// LoginThrottleConfig represents brute-force protection for password logins
+type LoginThrottleConfig struct {
+	// Store is "redis" to share counters between instances or "memory" for a
+	// single instance
+	Store string `mapstructure:"store"`
+	// MaxAttempts failures within Window lock the account; IPMaxAttempts
+	// failures lock the client IP
+	MaxAttempts     int           `mapstructure:"max_attempts"`
+	IPMaxAttempts   int           `mapstructure:"ip_max_attempts"`
+	Window          time.Duration `mapstructure:"window"`
+	LockoutDuration time.Duration `mapstructure:"lockout_duration"`
+	// BaseDelay is the wait after an account's first failure, doubled with
+	// every further failure up to MaxDelay
+	BaseDelay time.Duration `mapstructure:"base_delay"`
+	MaxDelay  time.Duration `mapstructure:"max_delay"`
+}
+
 // LoadConfig loads configuration from file using viper
 func LoadConfig(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
@@ -80,10 +186,28 @@ func LoadConfig(configPath string) (*Config, error) {
 	viper.SetDefault("database.type", "postgresql")
 	viper.SetDefault("database.postgres.sslmode", "disable")
 	viper.SetDefault("database.postgres.timezone", "UTC")
+	viper.SetDefault("jwt.algorithm", "HS256")
 	viper.SetDefault("jwt.access_duration", "15m")
 	viper.SetDefault("jwt.refresh_duration", "10080m")
+	viper.SetDefault("jwt.issuer", "go-gin-boilerplate")
+	viper.SetDefault("jwt.audience", "go-gin-boilerplate")
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", "6379")
+	viper.SetDefault("authz.deny_by_default", true)
+	viper.SetDefault("oidc.state_ttl", "10m")
+	viper.SetDefault("oauth.code_ttl", "1m")
+	viper.SetDefault("mfa.issuer", "go-gin-boilerplate")
+	viper.SetDefault("mail.driver", "log")
+	viper.SetDefault("mail.from", "no-reply@localhost")
+	viper.SetDefault("mail.link_base_url", "http://localhost:3000")
+	viper.SetDefault("mail.smtp.port", "587")
+	viper.SetDefault("login_throttle.store", "redis")
+	viper.SetDefault("login_throttle.max_attempts", 5)
+	viper.SetDefault("login_throttle.ip_max_attempts", 50)
+	viper.SetDefault("login_throttle.window", "15m")
+	viper.SetDefault("login_throttle.lockout_duration", "15m")
+	viper.SetDefault("login_throttle.base_delay", "1s")
+	viper.SetDefault("login_throttle.max_delay", "30s")
 
 	// Read configuration file
 	if err := viper.ReadInConfig(); err != nil {
@@ -128,8 +252,45 @@ func LoadConfigFromEnv(env string) (*Config, error) {
 
 // validateConfig validates required configuration fields
 func validateConfig(config *Config) error {
-	if config.JWT.Secret == "" {
-		return fmt.Errorf("JWT secret is required")
+	if len(config.JWT.Keys) > 0 {
+		if config.JWT.ActiveKid == "" {
+			return fmt.Errorf("JWT active_kid is required when keys are configured")
+		}
+	} else if strings.HasPrefix(config.JWT.Algorithm, "HS") {
+		if config.JWT.Secret == "" {
+			return fmt.Errorf("JWT secret is required")
+		}
+	} else if config.JWT.PrivateKey == "" && config.JWT.PrivateKeyFile == "" {
+		return fmt.Errorf("JWT private key or private key file is required for %s", config.JWT.Algorithm)
+	}
+
+	for name, provider := range config.OIDC.Providers {
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return fmt.Errorf("oidc provider %s requires issuer, client_id and redirect_url", name)
+		}
+	}
+
+	switch config.Mail.Driver {
+	case "log":
+		// Logged reset and verification links are bearer credentials
+		if config.IsProduction() {
+			return fmt.Errorf("the log mail driver cannot be used in production")
+		}
+	case "smtp":
+		if config.Mail.SMTP.Host == "" {
+			return fmt.Errorf("smtp host is required for the smtp mail driver")
+		}
+	default:
+		return fmt.Errorf("unsupported mail driver: %s", config.Mail.Driver)
+	}
+
+	switch config.LoginThrottle.Store {
+	case "redis", "memory":
+	default:
+		return fmt.Errorf("unsupported login throttle store: %s", config.LoginThrottle.Store)
+	}
+	if config.LoginThrottle.MaxAttempts <= 0 || config.LoginThrottle.IPMaxAttempts <= 0 {
+		return fmt.Errorf("login throttle max_attempts and ip_max_attempts must be positive")
 	}
 
 	if config.Database.Type == "" {
@@ -202,8 +363,15 @@ func (c *Config) PrintConfig() {
 	log.Printf("Configuration loaded:")
 	log.Printf("  Environment: %s", c.App.Env)
 	log.Printf("  Server Port: %s", c.Server.Port)
+	log.Printf("  Trusted Proxies: %v", c.Server.TrustedProxies)
 	log.Printf("  Database Type: %s", c.Database.Type)
 	log.Printf("  Redis: %s", c.GetRedisAddr())
+	log.Printf("  JWT Algorithm: %s", c.JWT.Algorithm)
 	log.Printf("  JWT Access Duration: %s", c.JWT.AccessDuration)
 	log.Printf("  JWT Refresh Duration: %s", c.JWT.RefreshDuration)
+	log.Printf("  JWT Issuer: %s", c.JWT.Issuer)
+	log.Printf("  JWT Audience: %s", c.JWT.Audience)
+	log.Printf("  OIDC Providers: %d", len(c.OIDC.Providers))
+	log.Printf("  Mail Driver: %s", c.Mail.Driver)
+	log.Printf("  Login Lockout: %d attempts per %s", c.LoginThrottle.MaxAttempts, c.LoginThrottle.Window)
 }
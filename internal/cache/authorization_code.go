@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type redisAuthorizationCodeStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewAuthorizationCodeStore(client *redis.Client, ttl time.Duration) port.AuthorizationCodeStore {
+	return &redisAuthorizationCodeStore{client: client, ttl: ttl}
+}
+
+func authorizationCodeKey(code string) string {
+	return "oauth_code:" + code
+}
+
+func (s *redisAuthorizationCodeStore) Save(ctx context.Context, code string, authCode *domain.AuthorizationCode) error {
+	data, err := json.Marshal(authCode)
+	if err != nil {
+		return err
+	}
+	return s.client.WithContext(ctx).Set(authorizationCodeKey(code), data, s.ttl).Err()
+}
+
+func (s *redisAuthorizationCodeStore) Consume(ctx context.Context, code string) (*domain.AuthorizationCode, error) {
+	// GET and DEL in one transaction so a code can be redeemed only once
+	pipe := s.client.WithContext(ctx).TxPipeline()
+	get := pipe.Get(authorizationCodeKey(code))
+	pipe.Del(authorizationCodeKey(code))
+	if _, err := pipe.Exec(); err != nil {
+		if err == redis.Nil {
+			return nil, domain.ErrOAuthInvalidGrant
+		}
+		return nil, err
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var authCode domain.AuthorizationCode
+	if err := json.Unmarshal(data, &authCode); err != nil {
+		return nil, err
+	}
+	return &authCode, nil
+}
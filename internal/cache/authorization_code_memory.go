@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"sync"
+	"time"
+)
+
+type memoryAuthorizationCode struct {
+	authCode  domain.AuthorizationCode
+	expiresAt time.Time
+}
+
+type memoryAuthorizationCodeStore struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	codes map[string]memoryAuthorizationCode
+}
+
+// NewMemoryAuthorizationCodeStore returns an in-process AuthorizationCodeStore
+// for tests and single-instance development setups
+func NewMemoryAuthorizationCodeStore(ttl time.Duration) port.AuthorizationCodeStore {
+	return &memoryAuthorizationCodeStore{ttl: ttl, codes: make(map[string]memoryAuthorizationCode)}
+}
+
+func (s *memoryAuthorizationCodeStore) Save(_ context.Context, code string, authCode *domain.AuthorizationCode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.codes[code] = memoryAuthorizationCode{authCode: *authCode, expiresAt: time.Now().Add(s.ttl)}
+	return nil
+}
+
+func (s *memoryAuthorizationCodeStore) Consume(_ context.Context, code string) (*domain.AuthorizationCode, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.codes[code]
+	delete(s.codes, code)
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, domain.ErrOAuthInvalidGrant
+	}
+	return &entry.authCode, nil
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type redisLoginAttemptStore struct {
+	client *redis.Client
+}
+
+func NewLoginAttemptStore(client *redis.Client) port.LoginAttemptStore {
+	return &redisLoginAttemptStore{client: client}
+}
+
+func loginAttemptsKey(key string) string {
+	return "login_attempts:" + key
+}
+
+func loginLockKey(key string) string {
+	return "login_lock:" + key
+}
+
+// forgiveLoginAttempt decrements a live count without recreating an expired one
+var forgiveLoginAttempt = redis.NewScript(`
+if tonumber(redis.call("HGET", KEYS[1], "count") or "0") > 0 then
+	return redis.call("HINCRBY", KEYS[1], "count", -1)
+end
+return 0
+`)
+
+func (s *redisLoginAttemptStore) RecordAttempt(ctx context.Context, key string, at time.Time, window time.Duration) (domain.LoginAttempts, error) {
+	// Read the previous attempt, count, stamp and extend the window in one
+	// transaction so concurrent attempts are ordered
+	pipe := s.client.WithContext(ctx).TxPipeline()
+	last := pipe.HGet(loginAttemptsKey(key), "last")
+	count := pipe.HIncrBy(loginAttemptsKey(key), "count", 1)
+	pipe.HSet(loginAttemptsKey(key), "last", at.UnixNano())
+	pipe.PExpire(loginAttemptsKey(key), window)
+	lock := pipe.Get(loginLockKey(key))
+	if _, err := pipe.Exec(); err != nil && err != redis.Nil {
+		return domain.LoginAttempts{}, err
+	}
+
+	attempts := domain.LoginAttempts{Count: int(count.Val())}
+	if previous, err := strconv.ParseInt(last.Val(), 10, 64); err == nil {
+		attempts.LastAttempt = time.Unix(0, previous)
+	}
+	if until, err := lock.Int64(); err == nil {
+		attempts.LockedUntil = time.Unix(0, until)
+	}
+	return attempts, nil
+}
+
+func (s *redisLoginAttemptStore) Forgive(ctx context.Context, key string) error {
+	return forgiveLoginAttempt.Run(s.client.WithContext(ctx), []string{loginAttemptsKey(key)}).Err()
+}
+
+func (s *redisLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	pipe := s.client.WithContext(ctx).TxPipeline()
+	pipe.Set(loginLockKey(key), until.UnixNano(), ttl)
+	pipe.Del(loginAttemptsKey(key))
+	_, err := pipe.Exec()
+	return err
+}
+
+func (s *redisLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	return s.client.WithContext(ctx).Del(loginAttemptsKey(key), loginLockKey(key)).Err()
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"sync"
+	"time"
+)
+
+type memoryLoginAttempts struct {
+	attempts  domain.LoginAttempts
+	expiresAt time.Time
+}
+
+type memoryLoginAttemptStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryLoginAttempts
+}
+
+// NewMemoryLoginAttemptStore returns an in-process LoginAttemptStore for
+// tests and single-instance setups without Redis
+func NewMemoryLoginAttemptStore() port.LoginAttemptStore {
+	return &memoryLoginAttemptStore{entries: make(map[string]*memoryLoginAttempts)}
+}
+
+func (s *memoryLoginAttemptStore) RecordAttempt(_ context.Context, key string, at time.Time, window time.Duration) (domain.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key)
+	previous := entry.attempts.LastAttempt
+	entry.attempts.Count++
+	entry.attempts.LastAttempt = at
+	if expiresAt := at.Add(window); expiresAt.After(entry.expiresAt) {
+		entry.expiresAt = expiresAt
+	}
+	s.entries[key] = entry
+
+	attempts := entry.attempts
+	attempts.LastAttempt = previous
+	return attempts, nil
+}
+
+func (s *memoryLoginAttemptStore) Forgive(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry := s.entry(key); entry.attempts.Count > 0 {
+		entry.attempts.Count--
+	}
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) Lock(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &memoryLoginAttempts{
+		attempts:  domain.LoginAttempts{LockedUntil: until},
+		expiresAt: until,
+	}
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// entry returns the live entry for key, dropping it once expired. The caller
+// must hold s.mu.
+func (s *memoryLoginAttemptStore) entry(key string) *memoryLoginAttempts {
+	entry, ok := s.entries[key]
+	if !ok {
+		return &memoryLoginAttempts{}
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return &memoryLoginAttempts{}
+	}
+	return entry
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type redisOIDCStateStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewOIDCStateStore(client *redis.Client, ttl time.Duration) port.OIDCStateStore {
+	return &redisOIDCStateStore{client: client, ttl: ttl}
+}
+
+func oidcStateKey(state string) string {
+	return "oidc_state:" + state
+}
+
+func (s *redisOIDCStateStore) Save(ctx context.Context, state string, authState *domain.OIDCAuthState) error {
+	data, err := json.Marshal(authState)
+	if err != nil {
+		return err
+	}
+	return s.client.WithContext(ctx).Set(oidcStateKey(state), data, s.ttl).Err()
+}
+
+func (s *redisOIDCStateStore) Consume(ctx context.Context, state string) (*domain.OIDCAuthState, error) {
+	// GET and DEL in one transaction so a state can be redeemed only once
+	pipe := s.client.WithContext(ctx).TxPipeline()
+	get := pipe.Get(oidcStateKey(state))
+	pipe.Del(oidcStateKey(state))
+	if _, err := pipe.Exec(); err != nil {
+		if err == redis.Nil {
+			return nil, domain.ErrOIDCStateInvalid
+		}
+		return nil, err
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var authState domain.OIDCAuthState
+	if err := json.Unmarshal(data, &authState); err != nil {
+		return nil, err
+	}
+	return &authState, nil
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"sync"
+	"time"
+)
+
+type memoryOIDCState struct {
+	authState domain.OIDCAuthState
+	expiresAt time.Time
+}
+
+type memoryOIDCStateStore struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	states map[string]memoryOIDCState
+}
+
+// NewMemoryOIDCStateStore returns an in-process OIDCStateStore for tests and
+// single-instance development setups
+func NewMemoryOIDCStateStore(ttl time.Duration) port.OIDCStateStore {
+	return &memoryOIDCStateStore{ttl: ttl, states: make(map[string]memoryOIDCState)}
+}
+
+func (s *memoryOIDCStateStore) Save(_ context.Context, state string, authState *domain.OIDCAuthState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state] = memoryOIDCState{authState: *authState, expiresAt: time.Now().Add(s.ttl)}
+	return nil
+}
+
+func (s *memoryOIDCStateStore) Consume(_ context.Context, state string) (*domain.OIDCAuthState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.states[state]
+	delete(s.states, state)
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, domain.ErrOIDCStateInvalid
+	}
+	return &entry.authState, nil
+}
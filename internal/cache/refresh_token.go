@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type redisRefreshTokenStore struct {
+	client *redis.Client
+	// familyTTL bounds how long a family revocation marker is kept; it must be
+	// at least the refresh token lifetime so no token of the family outlives it
+	familyTTL time.Duration
+}
+
+func NewRefreshTokenStore(client *redis.Client, familyTTL time.Duration) port.RefreshTokenStore {
+	return &redisRefreshTokenStore{client: client, familyTTL: familyTTL}
+}
+
+func refreshTokenKey(jti string) string {
+	return "refresh_token:" + jti
+}
+
+func refreshTokenUsedKey(jti string) string {
+	return "refresh_token:" + jti + ":used"
+}
+
+func refreshFamilyRevokedKey(familyID string) string {
+	return "refresh_family:" + familyID + ":revoked"
+}
+
+func (s *redisRefreshTokenStore) Save(ctx context.Context, record *domain.RefreshTokenRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.WithContext(ctx).Set(refreshTokenKey(record.JTI), data, time.Until(record.ExpiresAt)).Err()
+}
+
+func (s *redisRefreshTokenStore) MarkUsed(ctx context.Context, jti string) (*domain.RefreshTokenRecord, error) {
+	client := s.client.WithContext(ctx)
+
+	data, err := client.Get(refreshTokenKey(jti)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, domain.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+
+	var record domain.RefreshTokenRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+
+	// SETNX makes the first use win; any later attempt is a replay
+	ok, err := client.SetNX(refreshTokenUsedKey(jti), 1, time.Until(record.ExpiresAt)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &record, domain.ErrRefreshTokenReused
+	}
+
+	return &record, nil
+}
+
+func (s *redisRefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	return s.client.WithContext(ctx).Set(refreshFamilyRevokedKey(familyID), 1, s.familyTTL).Err()
+}
+
+func (s *redisRefreshTokenStore) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	n, err := s.client.WithContext(ctx).Exists(refreshFamilyRevokedKey(familyID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"sync"
+	"time"
+)
+
+type memoryRefreshTokenStore struct {
+	mu       sync.Mutex
+	records  map[string]domain.RefreshTokenRecord
+	used     map[string]bool
+	families map[string]bool
+}
+
+// NewMemoryRefreshTokenStore returns an in-process RefreshTokenStore for tests
+// and single-instance development setups
+func NewMemoryRefreshTokenStore() port.RefreshTokenStore {
+	return &memoryRefreshTokenStore{
+		records:  make(map[string]domain.RefreshTokenRecord),
+		used:     make(map[string]bool),
+		families: make(map[string]bool),
+	}
+}
+
+func (s *memoryRefreshTokenStore) Save(_ context.Context, record *domain.RefreshTokenRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.JTI] = *record
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) MarkUsed(_ context.Context, jti string) (*domain.RefreshTokenRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[jti]
+	if !ok || time.Now().After(record.ExpiresAt) {
+		return nil, domain.ErrRefreshTokenNotFound
+	}
+	if s.used[jti] {
+		return &record, domain.ErrRefreshTokenReused
+	}
+	s.used[jti] = true
+	return &record, nil
+}
+
+func (s *memoryRefreshTokenStore) RevokeFamily(_ context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.families[familyID] = true
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) IsFamilyRevoked(_ context.Context, familyID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.families[familyID], nil
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/port"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type redisRevocationStore struct {
+	client *redis.Client
+	// subjectTTL bounds how long a subject cut-off is kept; it must be at
+	// least the longest token lifetime so no revoked token outlives it
+	subjectTTL time.Duration
+}
+
+func NewRevocationStore(client *redis.Client, subjectTTL time.Duration) port.TokenRevocationStore {
+	return &redisRevocationStore{client: client, subjectTTL: subjectTTL}
+}
+
+func revokedTokenKey(jti string) string {
+	return "revoked_token:" + jti
+}
+
+func revokedSubjectKey(subject string) string {
+	return "revoked_subject:" + subject
+}
+
+func (s *redisRevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.WithContext(ctx).Set(revokedTokenKey(jti), 1, ttl).Err()
+}
+
+func (s *redisRevocationStore) RevokeOnce(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return false, nil
+	}
+	return s.client.WithContext(ctx).SetNX(revokedTokenKey(jti), 1, ttl).Result()
+}
+
+func (s *redisRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := s.client.WithContext(ctx).Exists(revokedTokenKey(jti)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (s *redisRevocationStore) RevokeSubject(ctx context.Context, subject string, at time.Time) error {
+	return s.client.WithContext(ctx).Set(revokedSubjectKey(subject), at.Unix(), s.subjectTTL).Err()
+}
+
+func (s *redisRevocationStore) IsSubjectRevoked(ctx context.Context, subject string, issuedAt time.Time) (bool, error) {
+	cutoff, err := s.client.WithContext(ctx).Get(revokedSubjectKey(subject)).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+	return issuedAt.Unix() <= cutoff, nil
+}
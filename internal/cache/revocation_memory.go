@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/port"
+	"sync"
+	"time"
+)
+
+type memoryRevocationStore struct {
+	mu       sync.Mutex
+	tokens   map[string]time.Time
+	subjects map[string]int64
+}
+
+// NewMemoryRevocationStore returns an in-process TokenRevocationStore for
+// tests and single-instance development setups
+func NewMemoryRevocationStore() port.TokenRevocationStore {
+	return &memoryRevocationStore{
+		tokens:   make(map[string]time.Time),
+		subjects: make(map[string]int64),
+	}
+}
+
+func (s *memoryRevocationStore) Revoke(_ context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+func (s *memoryRevocationStore) RevokeOnce(_ context.Context, jti string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if !now.Before(expiresAt) {
+		return false, nil
+	}
+	if revokedUntil, ok := s.tokens[jti]; ok && now.Before(revokedUntil) {
+		return false, nil
+	}
+	s.tokens[jti] = expiresAt
+	return true, nil
+}
+
+func (s *memoryRevocationStore) IsRevoked(_ context.Context, jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.tokens[jti]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+func (s *memoryRevocationStore) RevokeSubject(_ context.Context, subject string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subjects[subject] = at.Unix()
+	return nil
+}
+
+func (s *memoryRevocationStore) IsSubjectRevoked(_ context.Context, subject string, issuedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff, ok := s.subjects[subject]
+	return ok && issuedAt.Unix() <= cutoff, nil
+}
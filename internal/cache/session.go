@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type redisSessionStore struct {
+	client *redis.Client
+}
+
+func NewSessionStore(client *redis.Client) port.SessionStore {
+	return &redisSessionStore{client: client}
+}
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+func userSessionsKey(userID string) string {
+	return "user_sessions:" + userID
+}
+
+func (s *redisSessionStore) Save(ctx context.Context, session *domain.Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	// The per-user index lives as long as the user's newest session
+	pipe := s.client.WithContext(ctx).TxPipeline()
+	pipe.Set(sessionKey(session.ID), data, ttl)
+	pipe.SAdd(userSessionsKey(session.UserID), session.ID)
+	pipe.Expire(userSessionsKey(session.UserID), ttl)
+	_, err = pipe.Exec()
+	return err
+}
+
+func (s *redisSessionStore) Get(ctx context.Context, id string) (*domain.Session, error) {
+	data, err := s.client.WithContext(ctx).Get(sessionKey(id)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	var session domain.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *redisSessionStore) ListByUser(ctx context.Context, userID string) ([]domain.Session, error) {
+	ids, err := s.client.WithContext(ctx).SMembers(userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]domain.Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := s.Get(ctx, id)
+		if err == domain.ErrSessionNotFound {
+			// Expired sessions are dropped from the index lazily
+			s.client.WithContext(ctx).SRem(userSessionsKey(userID), id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
+
+func (s *redisSessionStore) Delete(ctx context.Context, userID, id string) error {
+	pipe := s.client.WithContext(ctx).TxPipeline()
+	pipe.Del(sessionKey(id))
+	pipe.SRem(userSessionsKey(userID), id)
+	_, err := pipe.Exec()
+	return err
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"sync"
+	"time"
+)
+
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]domain.Session
+}
+
+// NewMemorySessionStore returns an in-process SessionStore for tests and
+// single-instance development setups
+func NewMemorySessionStore() port.SessionStore {
+	return &memorySessionStore{sessions: make(map[string]domain.Session)}
+}
+
+func (s *memorySessionStore) Save(_ context.Context, session *domain.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+func (s *memorySessionStore) Get(_ context.Context, id string) (*domain.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok || time.Now().After(session.ExpiresAt) {
+		return nil, domain.ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (s *memorySessionStore) ListByUser(_ context.Context, userID string) ([]domain.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var sessions []domain.Session
+	for _, session := range s.sessions {
+		if session.UserID == userID && now.Before(session.ExpiresAt) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (s *memorySessionStore) Delete(_ context.Context, userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session, ok := s.sessions[id]; ok && session.UserID == userID {
+		delete(s.sessions, id)
+	}
+	return nil
+}
@@ -5,8 +5,13 @@ import "context"
 type BaseRepository interface {
 	Create(ctx context.Context, collection string, model any) error
 	GetById(ctx context.Context, collection string, id string, result any) error
-	GetAll(ctx context.Context, collection string, result any, filter any) error
+	GetAll(ctx context.Context, collection string, result any, filter Filter) error
+	Find(ctx context.Context, collection string, result any, query Query) error
+	FindOne(ctx context.Context, collection string, result any, query Query) error
+	Count(ctx context.Context, collection string, model any, filter Filter) (int64, error)
 	GetByField(ctx context.Context, collection string, field string, value any, result any) error
-	UpdateById(ctx context.Context, collection string, id string, update any) error
-	DeleteById(ctx context.Context, collection string, id string) error
+	// UpdateById and DeleteById take a pointer to a zero entity as the model,
+	// which tells backends without collections of their own the table
+	UpdateById(ctx context.Context, collection string, model any, id string, update any) error
+	DeleteById(ctx context.Context, collection string, model any, id string) error
 }
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"encoding/json"
+	"go-gin-boilerplate/internal/domain"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// fieldResolver returns the name a backend stores an entity field under and
+// the field's Go type, given the field's JSON name
+type fieldResolver func(field string) (string, reflect.Type, error)
+
+// gormFields resolves fields of the model to their column names
+func gormFields(tx *gorm.DB, model any) (fieldResolver, error) {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return func(field string) (string, reflect.Type, error) {
+		for _, schemaField := range stmt.Schema.Fields {
+			if schemaField.DBName != "" && jsonName(schemaField.StructField) == field {
+				return schemaField.DBName, schemaField.FieldType, nil
+			}
+		}
+		return "", nil, unknownField(field)
+	}, nil
+}
+
+// bsonFields resolves fields of the model to their document keys
+func bsonFields(model any) fieldResolver {
+	entityType := entityType(model)
+	return func(field string) (string, reflect.Type, error) {
+		if entityType.Kind() == reflect.Struct {
+			for i := 0; i < entityType.NumField(); i++ {
+				structField := entityType.Field(i)
+				if !structField.IsExported() || jsonName(structField) != field {
+					continue
+				}
+				key := strings.Split(structField.Tag.Get("bson"), ",")[0]
+				if key == "-" {
+					break
+				}
+				if key == "" {
+					key = strings.ToLower(structField.Name)
+				}
+				return key, structField.Type, nil
+			}
+		}
+		return "", nil, unknownField(field)
+	}
+}
+
+// FieldValues returns the values of the entity's fields, named by their JSON
+// names, e.g. to build a pagination cursor from the last entity of a page
+func FieldValues(entity any, fields []string) ([]any, error) {
+	value := reflect.Indirect(reflect.ValueOf(entity))
+	values := make([]any, 0, len(fields))
+	for _, field := range fields {
+		found := false
+		if value.Kind() == reflect.Struct {
+			for i := 0; i < value.NumField(); i++ {
+				if value.Type().Field(i).IsExported() && jsonName(value.Type().Field(i)) == field {
+					values = append(values, value.Field(i).Interface())
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return nil, unknownField(field)
+		}
+	}
+	return values, nil
+}
+
+// entityType returns the struct type of a model given as a struct, a pointer
+// or a pointer to a slice of either
+func entityType(model any) reflect.Type {
+	t := reflect.TypeOf(model)
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	return t
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func unknownField(field string) error {
+	return domain.NewValidationError("unknown field: "+field, domain.FieldError{Field: field, Message: "is not a known field"})
+}
+
+// coerce converts a value, e.g. one parsed from a query string or a cursor,
+// to the type of the field it is compared with
+func coerce(field string, value any, t reflect.Type) (any, error) {
+	if value == nil || reflect.TypeOf(value) == t {
+		return value, nil
+	}
+	target := reflect.New(t)
+	if raw, err := json.Marshal(value); err == nil && json.Unmarshal(raw, target.Interface()) == nil {
+		return target.Elem().Interface(), nil
+	}
+	if s, ok := value.(string); ok && json.Unmarshal([]byte(s), target.Interface()) == nil {
+		return target.Elem().Interface(), nil
+	}
+	return nil, domain.NewValidationError("invalid value for field: "+field, domain.FieldError{Field: field, Message: "has an invalid value"})
+}
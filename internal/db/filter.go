@@ -0,0 +1,352 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Filter selects the entities whose fields match every condition. It is
+// independent of the database so that repositories behave the same on every
+// BaseRepository backend; an empty filter matches everything.
+type Filter []Condition
+
+// Operator compares a field with the value of a condition, or combines the
+// conditions of a group
+type Operator string
+
+const (
+	OpEq  Operator = "eq"
+	OpNe  Operator = "ne"
+	OpGt  Operator = "gt"
+	OpGte Operator = "gte"
+	OpLt  Operator = "lt"
+	OpLte Operator = "lte"
+	// OpIn matches any of the values of a slice
+	OpIn Operator = "in"
+	// OpContains matches strings containing the value, ignoring case
+	OpContains Operator = "contains"
+	// OpLike matches strings against a SQL LIKE pattern, where % matches any
+	// run of characters and _ a single one; \ escapes either
+	OpLike Operator = "like"
+
+	// OpAnd, OpOr and OpNot combine the Conditions of a group
+	OpAnd Operator = "and"
+	OpOr  Operator = "or"
+	OpNot Operator = "not"
+)
+
+// Condition compares a field with a value. Fields are named by the entity's
+// JSON names; each backend maps them to the column or key they are stored
+// under, so a field unknown to the entity is a validation error.
+//
+// A condition with OpAnd, OpOr or OpNot instead combines its Conditions, so
+// conditions nest into arbitrary boolean expressions.
+type Condition struct {
+	Field      string
+	Op         Operator
+	Value      any
+	Conditions Filter
+}
+
+// Eq returns a condition matching entities whose field equals value
+func Eq(field string, value any) Condition {
+	return Condition{Field: field, Op: OpEq, Value: value}
+}
+
+// Ne returns a condition matching entities whose field differs from value
+func Ne(field string, value any) Condition {
+	return Condition{Field: field, Op: OpNe, Value: value}
+}
+
+// Gt returns a condition matching entities whose field is greater than value
+func Gt(field string, value any) Condition {
+	return Condition{Field: field, Op: OpGt, Value: value}
+}
+
+// Gte returns a condition matching entities whose field is at least value
+func Gte(field string, value any) Condition {
+	return Condition{Field: field, Op: OpGte, Value: value}
+}
+
+// Lt returns a condition matching entities whose field is less than value
+func Lt(field string, value any) Condition {
+	return Condition{Field: field, Op: OpLt, Value: value}
+}
+
+// Lte returns a condition matching entities whose field is at most value
+func Lte(field string, value any) Condition {
+	return Condition{Field: field, Op: OpLte, Value: value}
+}
+
+// In returns a condition matching entities whose field equals any of values
+func In(field string, values ...any) Condition {
+	return Condition{Field: field, Op: OpIn, Value: values}
+}
+
+// Between returns a condition matching entities whose field lies in the
+// inclusive range from..to
+func Between(field string, from, to any) Condition {
+	return And(Gte(field, from), Lte(field, to))
+}
+
+// Like returns a condition matching entities whose field matches a LIKE pattern
+func Like(field string, pattern string) Condition {
+	return Condition{Field: field, Op: OpLike, Value: pattern}
+}
+
+// Contains returns a condition matching entities whose field contains s,
+// ignoring case
+func Contains(field string, s string) Condition {
+	return Condition{Field: field, Op: OpContains, Value: s}
+}
+
+// And returns a condition matching entities that match all conditions
+func And(conditions ...Condition) Condition {
+	return Condition{Op: OpAnd, Conditions: conditions}
+}
+
+// Or returns a condition matching entities that match any of the conditions
+func Or(conditions ...Condition) Condition {
+	return Condition{Op: OpOr, Conditions: conditions}
+}
+
+// Not returns a condition matching entities that do not match condition
+func Not(condition Condition) Condition {
+	return Condition{Op: OpNot, Conditions: Filter{condition}}
+}
+
+// Apply adds the filter to a GORM query on the given model as WHERE clauses
+func (f Filter) Apply(tx *gorm.DB, model any) (*gorm.DB, error) {
+	columns, err := gormFields(tx, model)
+	if err != nil {
+		return nil, err
+	}
+	for _, condition := range f {
+		expr, err := condition.gormExpr(columns)
+		if err != nil {
+			return nil, err
+		}
+		tx = tx.Where(expr)
+	}
+	return tx, nil
+}
+
+// BSON translates the filter on the given model into a MongoDB query
+// document. Conditions on the same key are combined with $and, as a
+// document holds each key once.
+func (f Filter) BSON(model any) (bson.D, error) {
+	return f.bson(bsonFields(model))
+}
+
+func (f Filter) bson(keys fieldResolver) (bson.D, error) {
+	doc := bson.D{}
+	seen := make(map[string]bool, len(f))
+	repeated := false
+	for _, condition := range f {
+		element, err := condition.bsonElement(keys)
+		if err != nil {
+			return nil, err
+		}
+		repeated = repeated || seen[element.Key]
+		seen[element.Key] = true
+		doc = append(doc, element)
+	}
+	if !repeated {
+		return doc, nil
+	}
+
+	and := make(bson.A, 0, len(doc))
+	for _, element := range doc {
+		and = append(and, bson.D{element})
+	}
+	return bson.D{{Key: "$and", Value: and}}, nil
+}
+
+func (c Condition) gormExpr(columns fieldResolver) (clause.Expression, error) {
+	if c.isGroup() {
+		if err := c.checkGroup(); err != nil {
+			return nil, err
+		}
+		exprs := make([]clause.Expression, 0, len(c.Conditions))
+		for _, condition := range c.Conditions {
+			expr, err := condition.gormExpr(columns)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		}
+		switch c.Op {
+		case OpOr:
+			return clause.Or(exprs...), nil
+		case OpNot:
+			return clause.Not(exprs...), nil
+		default:
+			return clause.And(exprs...), nil
+		}
+	}
+
+	name, value, err := c.resolve(columns)
+	if err != nil {
+		return nil, err
+	}
+	column := clause.Column{Name: name}
+	switch c.Op {
+	case OpEq, "":
+		return clause.Eq{Column: column, Value: value}, nil
+	case OpNe:
+		return clause.Neq{Column: column, Value: value}, nil
+	case OpGt:
+		return clause.Gt{Column: column, Value: value}, nil
+	case OpGte:
+		return clause.Gte{Column: column, Value: value}, nil
+	case OpLt:
+		return clause.Lt{Column: column, Value: value}, nil
+	case OpLte:
+		return clause.Lte{Column: column, Value: value}, nil
+	case OpIn:
+		return clause.IN{Column: column, Values: value.([]any)}, nil
+	case OpContains:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []any{column, "%" + likeEscaper.Replace(value.(string)) + "%"}}, nil
+	case OpLike:
+		return clause.Expr{SQL: "? LIKE ?", Vars: []any{column, value}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported filter operator: %s", c.Op)
+	}
+}
+
+func (c Condition) bsonElement(keys fieldResolver) (bson.E, error) {
+	if c.isGroup() {
+		if err := c.checkGroup(); err != nil {
+			return bson.E{}, err
+		}
+		docs := make(bson.A, 0, len(c.Conditions))
+		for _, condition := range c.Conditions {
+			doc, err := condition.bsonDoc(keys)
+			if err != nil {
+				return bson.E{}, err
+			}
+			docs = append(docs, doc)
+		}
+		// MongoDB negates whole documents with $nor; $not only applies to
+		// the operators of a single field
+		operator := map[Operator]string{OpAnd: "$and", OpOr: "$or", OpNot: "$nor"}[c.Op]
+		return bson.E{Key: operator, Value: docs}, nil
+	}
+
+	key, value, err := c.resolve(keys)
+	if err != nil {
+		return bson.E{}, err
+	}
+	switch c.Op {
+	case OpEq, "":
+		return bson.E{Key: key, Value: value}, nil
+	case OpNe, OpGt, OpGte, OpLt, OpLte, OpIn:
+		return bson.E{Key: key, Value: bson.D{{Key: "$" + string(c.Op), Value: value}}}, nil
+	case OpContains:
+		return bson.E{Key: key, Value: primitive.Regex{Pattern: regexp.QuoteMeta(value.(string)), Options: "i"}}, nil
+	case OpLike:
+		return bson.E{Key: key, Value: primitive.Regex{Pattern: likeRegexp(value.(string))}}, nil
+	default:
+		return bson.E{}, fmt.Errorf("unsupported filter operator: %s", c.Op)
+	}
+}
+
+// bsonDoc translates the condition into a document of its own, as the
+// members of $and, $or and $nor are. The conditions of an and group are
+// inlined into the document.
+func (c Condition) bsonDoc(keys fieldResolver) (bson.D, error) {
+	if c.Op == OpAnd {
+		if err := c.checkGroup(); err != nil {
+			return nil, err
+		}
+		return c.Conditions.bson(keys)
+	}
+	element, err := c.bsonElement(keys)
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{element}, nil
+}
+
+func (c Condition) isGroup() bool {
+	return c.Op == OpAnd || c.Op == OpOr || c.Op == OpNot
+}
+
+// checkGroup rejects empty groups, which SQL and MongoDB disagree on, and
+// negations of more than one condition
+func (c Condition) checkGroup() error {
+	if len(c.Conditions) == 0 {
+		return fmt.Errorf("%s filter needs at least one condition", c.Op)
+	}
+	if c.Op == OpNot && len(c.Conditions) != 1 {
+		return fmt.Errorf("not filter needs exactly one condition")
+	}
+	return nil
+}
+
+// resolve returns the stored name of the condition's field and its value
+// converted to the field's type
+func (c Condition) resolve(fields fieldResolver) (string, any, error) {
+	name, fieldType, err := fields(c.Field)
+	if err != nil {
+		return "", nil, err
+	}
+	switch c.Op {
+	case OpContains, OpLike:
+		s, ok := c.Value.(string)
+		if !ok {
+			return "", nil, fmt.Errorf("%s filter on %s needs a string", c.Op, c.Field)
+		}
+		return name, s, nil
+	case OpIn:
+		values := reflect.ValueOf(c.Value)
+		if values.Kind() != reflect.Slice {
+			return "", nil, fmt.Errorf("in filter on %s needs a slice", c.Field)
+		}
+		coerced := make([]any, values.Len())
+		for i := range coerced {
+			if coerced[i], err = coerce(c.Field, values.Index(i).Interface(), fieldType); err != nil {
+				return "", nil, err
+			}
+		}
+		return name, coerced, nil
+	default:
+		value, err := coerce(c.Field, c.Value, fieldType)
+		return name, value, err
+	}
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likeRegexp translates a LIKE pattern into the anchored regular expression
+// matching the same strings
+func likeRegexp(pattern string) string {
+	var re strings.Builder
+	re.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			re.WriteString(`[\s\S]*`)
+		case r == '_':
+			re.WriteString(`[\s\S]`)
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	re.WriteString("$")
+	return re.String()
+}
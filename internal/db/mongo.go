@@ -3,7 +3,9 @@ package db
 import (
 	"context"
 	"errors"
+	"fmt"
 	"go-gin-boilerplate/config"
+	"go-gin-boilerplate/internal/domain"
 	"log"
 	"time"
 
@@ -19,9 +21,37 @@ func InitMongo(config *config.DatabaseConfig) *mongo.Client {
 		log.Fatalf("Failed to connect to MongoDB: %v", err)
 	}
 	log.Println("Successfully connected to MongoDB!")
+
+	if err := EnsureMongoIndexes(context.Background(), client.Database(config.MongoDB.DBName)); err != nil {
+		log.Fatalf("Failed to create MongoDB indexes: %v", err)
+	}
 	return client
 }
 
+// mongoUniqueIndexes are the unique keys of each collection, which GORM
+// creates from the uniqueIndex tags on PostgreSQL. They make concurrent
+// duplicate inserts fail with a duplicate key error, reported as a conflict.
+var mongoUniqueIndexes = map[string][]bson.D{
+	"user":              {{{Key: "email", Value: 1}}},
+	"api_key":           {{{Key: "prefix", Value: 1}}},
+	"external_identity": {{{Key: "provider", Value: 1}, {Key: "subject", Value: 1}}},
+}
+
+// EnsureMongoIndexes creates the unique indexes of the collections in the
+// database; indexes that exist already are left alone
+func EnsureMongoIndexes(ctx context.Context, database *mongo.Database) error {
+	for collection, keys := range mongoUniqueIndexes {
+		models := make([]mongo.IndexModel, 0, len(keys))
+		for _, key := range keys {
+			models = append(models, mongo.IndexModel{Keys: key, Options: options.Index().SetUnique(true)})
+		}
+		if _, err := database.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("collection %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
 type mongoRepo struct {
 	client *mongo.Client
 	dbName string
@@ -35,90 +65,121 @@ func (mg *mongoRepo) Create(ctx context.Context, collection string, model any) e
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	_, err := mg.client.Database(mg.dbName).Collection(collection).InsertOne(ctx, model)
-	return err
+	return wrapMongoError(err)
 }
 
 func (mg *mongoRepo) GetById(ctx context.Context, collection string, id string, result any) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	err := mg.client.Database(mg.dbName).Collection(collection).FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	return wrapMongoError(mg.client.Database(mg.dbName).Collection(collection).FindOne(ctx, bson.M{"_id": id}).Decode(result))
+}
+
+func (mg *mongoRepo) GetAll(ctx context.Context, collection string, result any, filter Filter) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	doc, err := filter.BSON(result)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return errors.New("entity not found")
-		}
 		return err
 	}
-	return nil
+	cur, err := mg.client.Database(mg.dbName).Collection(collection).Find(ctx, doc)
+	if err != nil {
+		return wrapMongoError(err)
+	}
+	return wrapMongoError(cur.All(ctx, result))
 }
 
-func (mg *mongoRepo) GetAll(ctx context.Context, collection string, result any, filter any) error {
+func (mg *mongoRepo) Find(ctx context.Context, collection string, result any, query Query) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	mongoFilter := bson.M{}
-	if filter != nil {
-		// Use bson.Marshal and Unmarshal for automatic conversion
-		filterBytes, err := bson.Marshal(filter)
-		if err != nil {
-			return err
-		}
-		err = bson.Unmarshal(filterBytes, &mongoFilter)
-		if err != nil {
-			return err
-		}
-
-		// Remove empty/zero values to avoid matching empty strings
-		cleanFilter := bson.M{}
-		for key, value := range mongoFilter {
-			if value != nil && value != "" {
-				cleanFilter[key] = value
-			}
-		}
-		mongoFilter = cleanFilter
+	doc, opts, err := query.BSON(result)
+	if err != nil {
+		return err
 	}
+	cur, err := mg.client.Database(mg.dbName).Collection(collection).Find(ctx, doc, opts)
+	if err != nil {
+		return wrapMongoError(err)
+	}
+	return wrapMongoError(cur.All(ctx, result))
+}
 
-	cur, err := mg.client.Database(mg.dbName).Collection(collection).Find(ctx, mongoFilter)
+func (mg *mongoRepo) FindOne(ctx context.Context, collection string, result any, query Query) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	doc, sortDoc, projection, err := query.bson(result)
 	if err != nil {
 		return err
 	}
-	return cur.All(ctx, result)
+	opts := options.FindOne()
+	if sortDoc != nil {
+		opts.SetSort(sortDoc)
+	}
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	if query.Offset > 0 {
+		opts.SetSkip(int64(query.Offset))
+	}
+	return wrapMongoError(mg.client.Database(mg.dbName).Collection(collection).FindOne(ctx, doc, opts).Decode(result))
 }
 
-func (mg *mongoRepo) GetByField(ctx context.Context, collection string, field string, value any, result any) error {
+func (mg *mongoRepo) Count(ctx context.Context, collection string, model any, filter Filter) (int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	err := mg.client.Database(mg.dbName).Collection(collection).FindOne(ctx, bson.M{field: value}).Decode(result)
+
+	doc, err := filter.BSON(model)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return errors.New("entity not found")
-		}
-		return err
+		return 0, err
 	}
-	return nil
+	count, err := mg.client.Database(mg.dbName).Collection(collection).CountDocuments(ctx, doc)
+	return count, wrapMongoError(err)
+}
+
+func (mg *mongoRepo) GetByField(ctx context.Context, collection string, field string, value any, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return wrapMongoError(mg.client.Database(mg.dbName).Collection(collection).FindOne(ctx, bson.M{field: value}).Decode(result))
 }
 
-func (mg *mongoRepo) UpdateById(ctx context.Context, collection string, id string, update any) error {
+func (mg *mongoRepo) UpdateById(ctx context.Context, collection string, _ any, id string, update any) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	result, err := mg.client.Database(mg.dbName).Collection(collection).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
 	if err != nil {
-		return err
+		return wrapMongoError(err)
 	}
 	if result.MatchedCount == 0 {
-		return errors.New("entity not found")
+		return domain.NewNotFoundError("entity not found")
 	}
 	return nil
 }
 
-func (mg *mongoRepo) DeleteById(ctx context.Context, collection string, id string) error {
+func (mg *mongoRepo) DeleteById(ctx context.Context, collection string, _ any, id string) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	result, err := mg.client.Database(mg.dbName).Collection(collection).DeleteOne(ctx, bson.M{"_id": id})
 	if err != nil {
-		return err
+		return wrapMongoError(err)
 	}
 	if result.DeletedCount == 0 {
-		return errors.New("entity not found")
+		return domain.NewNotFoundError("entity not found")
 	}
 	return nil
 }
+
+// wrapMongoError classifies driver errors as domain errors. Anything else,
+// such as a lost connection, is returned unchanged and treated as internal.
+func wrapMongoError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return domain.WrapError(domain.ErrNotFound, "entity not found", err)
+	case mongo.IsDuplicateKeyError(err):
+		return domain.WrapError(domain.ErrConflict, "entity already exists", err)
+	default:
+		return err
+	}
+}
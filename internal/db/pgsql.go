@@ -7,6 +7,7 @@ import (
 	"go-gin-boilerplate/config"
 	"go-gin-boilerplate/internal/domain"
 	"log"
+	"reflect"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -28,6 +29,8 @@ func InitPgsql(config *config.DatabaseConfig) *gorm.DB {
 	gormConfig := &gorm.Config{
 		SkipDefaultTransaction: true,
 		Logger:                 logger.Default.LogMode(logger.Info),
+		// Report constraint violations as gorm errors so they can be classified
+		TranslateError: true,
 	}
 	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
 	if err != nil {
@@ -36,7 +39,7 @@ func InitPgsql(config *config.DatabaseConfig) *gorm.DB {
 
 	log.Println("Successfully connected to PostgreSQL!")
 
-	db.AutoMigrate(&domain.Foo{}, &domain.Bar{})
+	db.AutoMigrate(&domain.Foo{}, &domain.Bar{}, &domain.User{}, &domain.APIKey{}, &domain.ExternalIdentity{}, &domain.OAuthClient{})
 
 	return db
 }
@@ -50,95 +53,143 @@ func NewPgsqlRepository(db *gorm.DB) BaseRepository {
 }
 
 func (pg *pgsqlRepository) Create(ctx context.Context, _ string, model any) error {
-	return pg.db.WithContext(ctx).Create(model).Error
+	return wrapPgsqlError(pgsqlConn(ctx, pg.db).Create(model).Error)
 }
 
 func (pg *pgsqlRepository) GetById(ctx context.Context, _ string, id string, result any) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	err := pg.db.WithContext(ctx).First(result, "id = ?", id).Error
+	return wrapPgsqlError(pgsqlConn(ctx, pg.db).First(result, "id = ?", id).Error)
+}
+
+func (pg *pgsqlRepository) GetAll(ctx context.Context, _ string, result any, filter Filter) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	tx, err := filter.Apply(pgsqlConn(ctx, pg.db), result)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("entity not found")
-		}
 		return err
 	}
-	return nil
+	return wrapPgsqlError(tx.Find(result).Error)
 }
 
-func (pg *pgsqlRepository) GetAll(ctx context.Context, _ string, result any, filter any) error {
+func (pg *pgsqlRepository) Find(ctx context.Context, _ string, result any, query Query) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	return pg.db.WithContext(ctx).Find(result).Error
+	tx, err := query.Apply(pgsqlConn(ctx, pg.db), result)
+	if err != nil {
+		return err
+	}
+	return wrapPgsqlError(tx.Find(result).Error)
+}
+
+func (pg *pgsqlRepository) FindOne(ctx context.Context, _ string, result any, query Query) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	tx, err := query.Apply(pgsqlConn(ctx, pg.db), result)
+	if err != nil {
+		return err
+	}
+	return wrapPgsqlError(tx.Take(result).Error)
+}
+
+func (pg *pgsqlRepository) Count(ctx context.Context, _ string, model any, filter Filter) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	tx, err := filter.Apply(pgsqlConn(ctx, pg.db).Model(model), model)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := tx.Count(&count).Error; err != nil {
+		return 0, wrapPgsqlError(err)
+	}
+	return count, nil
 }
 
 func (pg *pgsqlRepository) GetByField(ctx context.Context, _ string, field string, value any, result any) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	condition := map[string]any{field: value}
-	res := pg.db.WithContext(ctx).Where(condition).First(result)
-	if res.Error != nil {
-		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
-			return errors.New("entity not found")
-		}
-		return res.Error
-	}
-	return nil
+	return wrapPgsqlError(pgsqlConn(ctx, pg.db).Where(condition).First(result).Error)
 }
 
-func (pg *pgsqlRepository) UpdateById(ctx context.Context, collection string, id string, update any) error {
+func (pg *pgsqlRepository) UpdateById(ctx context.Context, _ string, model any, id string, update any) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	model, err := pg.getModelByCollection(collection)
-	if err != nil {
-		return err
+	if values, ok := update.(map[string]any); ok {
+		var err error
+		if update, err = pg.serializeMapUpdate(ctx, model, values); err != nil {
+			return err
+		}
 	}
 
-	result := pg.db.WithContext(ctx).Model(model).Where("id = ?", id).Updates(update)
+	result := pgsqlConn(ctx, pg.db).Model(model).Where("id = ?", id).Updates(update)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return errors.New("entity not found")
-		}
-		return result.Error
+		return wrapPgsqlError(result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("entity not found")
+		return domain.NewNotFoundError("entity not found")
 	}
 
 	return nil
 }
 
-func (pg *pgsqlRepository) DeleteById(ctx context.Context, collection string, id string) error {
+func (pg *pgsqlRepository) DeleteById(ctx context.Context, _ string, model any, id string) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	model, err := pg.getModelByCollection(collection)
-	if err != nil {
-		return err
-	}
-
-	result := pg.db.WithContext(ctx).Delete(model, "id = ?", id)
+	result := pgsqlConn(ctx, pg.db).Delete(model, "id = ?", id)
 	if result.Error != nil {
-		return result.Error
+		return wrapPgsqlError(result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("entity not found")
+		return domain.NewNotFoundError("entity not found")
 	}
 
 	return nil
 }
 
-// getModelByCollection returns the appropriate model based on collection name
-func (pg *pgsqlRepository) getModelByCollection(collection string) (any, error) {
-	switch collection {
-	case "bar":
-		return &domain.Bar{}, nil
-	case "foo":
-		return &domain.Foo{}, nil
+// serializeMapUpdate encodes values of columns with a GORM serializer (e.g.
+// jsonb slices). GORM applies serializers to struct updates only, so map
+// updates would otherwise send the raw Go value to the driver.
+func (pg *pgsqlRepository) serializeMapUpdate(ctx context.Context, model any, values map[string]any) (map[string]any, error) {
+	stmt := &gorm.Statement{DB: pg.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+
+	serialized := make(map[string]any, len(values))
+	for column, value := range values {
+		field := stmt.Schema.LookUpField(column)
+		if field == nil || field.Serializer == nil {
+			serialized[column] = value
+			continue
+		}
+		encoded, err := field.Serializer.Value(ctx, field, reflect.ValueOf(model), value)
+		if err != nil {
+			return nil, err
+		}
+		serialized[column] = encoded
+	}
+	return serialized, nil
+}
+
+// wrapPgsqlError classifies GORM errors as domain errors. Anything else, such
+// as a lost connection, is returned unchanged and treated as internal.
+func wrapPgsqlError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.WrapError(domain.ErrNotFound, "entity not found", err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return domain.WrapError(domain.ErrConflict, "entity already exists", err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated), errors.Is(err, gorm.ErrCheckConstraintViolated), errors.Is(err, gorm.ErrInvalidField):
+		return domain.WrapError(domain.ErrValidation, "invalid entity", err)
 	default:
-		return nil, fmt.Errorf("unknown collection: %s", collection)
+		return err
 	}
 }
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Sort orders entities by a field, named by its JSON name
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query selects a page of the entities matching a filter. Pages are either
+// skipped with Offset or, for keyset pagination, start After the entity with
+// the given values of the Sort fields. Sort should end with a unique field
+// so that the order, and with it every page, is stable.
+//
+//	db.Query{
+//		Filter: db.Filter{db.Eq("status", "active"), db.Or(db.Like("name", "A%"), db.In("owner_id", a, b))},
+//		Sort:   []db.Sort{{Field: "name"}},
+//		Limit:  10,
+//		Select: []string{"id", "name"},
+//	}
+type Query struct {
+	Filter Filter
+	Sort   []Sort
+	// Limit is the maximum number of entities to return; 0 means no limit
+	Limit  int
+	Offset int
+	After  []any
+	// Select restricts the fields loaded into the results; empty loads all
+	Select []string
+}
+
+// Apply adds the query on the given model to a GORM query
+func (q Query) Apply(tx *gorm.DB, model any) (*gorm.DB, error) {
+	filter, err := q.filter()
+	if err != nil {
+		return nil, err
+	}
+	tx, err = filter.Apply(tx, model)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := gormFields(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(q.Select) > 0 {
+		selected := make([]string, 0, len(q.Select))
+		for _, field := range q.Select {
+			column, _, err := columns(field)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, column)
+		}
+		tx = tx.Select(selected)
+	}
+	for _, sort := range q.Sort {
+		column, _, err := columns(sort.Field)
+		if err != nil {
+			return nil, err
+		}
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: sort.Desc})
+	}
+	if q.Limit > 0 {
+		tx = tx.Limit(q.Limit)
+	}
+	if q.Offset > 0 {
+		tx = tx.Offset(q.Offset)
+	}
+	return tx, nil
+}
+
+// BSON translates the query on the given model into a MongoDB query
+// document and the find options for sorting, skipping and projecting
+func (q Query) BSON(model any) (bson.D, *options.FindOptions, error) {
+	doc, sortDoc, projection, err := q.bson(model)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := options.Find()
+	if sortDoc != nil {
+		opts.SetSort(sortDoc)
+	}
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	if q.Limit > 0 {
+		opts.SetLimit(int64(q.Limit))
+	}
+	if q.Offset > 0 {
+		opts.SetSkip(int64(q.Offset))
+	}
+	return doc, opts, nil
+}
+
+// bson returns the query document, sort document and projection of the
+// query, leaving the latter two nil when unused
+func (q Query) bson(model any) (doc bson.D, sortDoc bson.D, projection bson.D, err error) {
+	filter, err := q.filter()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keys := bsonFields(model)
+	if doc, err = filter.bson(keys); err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, sort := range q.Sort {
+		key, _, err := keys(sort.Field)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		direction := 1
+		if sort.Desc {
+			direction = -1
+		}
+		sortDoc = append(sortDoc, bson.E{Key: key, Value: direction})
+	}
+	for _, field := range q.Select {
+		key, _, err := keys(field)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		projection = append(projection, bson.E{Key: key, Value: 1})
+	}
+	return doc, sortDoc, projection, nil
+}
+
+// filter returns the query's filter together with the keyset condition
+func (q Query) filter() (Filter, error) {
+	if q.After == nil {
+		return q.Filter, nil
+	}
+	keyset, err := q.keyset()
+	if err != nil {
+		return nil, err
+	}
+	return append(q.Filter[:len(q.Filter):len(q.Filter)], keyset), nil
+}
+
+// keyset returns the condition matching entities that sort after the After
+// values: for any sort field, the entities equal in the fields before it and
+// beyond the After value in it
+func (q Query) keyset() (Condition, error) {
+	if len(q.After) != len(q.Sort) {
+		return Condition{}, errors.New("keyset pagination needs a value for every sort field")
+	}
+	alternatives := make([]Condition, 0, len(q.Sort))
+	for i, sort := range q.Sort {
+		conditions := make(Filter, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, Eq(q.Sort[j].Field, q.After[j]))
+		}
+		op := OpGt
+		if sort.Desc {
+			op = OpLt
+		}
+		conditions = append(conditions, Condition{Field: sort.Field, Op: op, Value: q.After[i]})
+		alternatives = append(alternatives, And(conditions...))
+	}
+	return Or(alternatives...), nil
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-gin-boilerplate/internal/port"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// pgsqlTxKey holds the GORM transaction of a unit of work in a context
+type pgsqlTxKey struct{}
+
+type pgsqlUnitOfWork struct {
+	db *gorm.DB
+}
+
+// NewPgsqlUnitOfWork runs units of work in GORM transactions, which the
+// pgsqlRepository on the same connection picks up from the context
+func NewPgsqlUnitOfWork(db *gorm.DB) port.UnitOfWork {
+	return &pgsqlUnitOfWork{db: db}
+}
+
+func (u *pgsqlUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	// A nested unit of work becomes a savepoint of the enclosing transaction
+	conn := u.db
+	if tx, ok := ctx.Value(pgsqlTxKey{}).(*gorm.DB); ok {
+		conn = tx
+	}
+	return conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, pgsqlTxKey{}, tx))
+	})
+}
+
+// pgsqlConn returns the transaction of the unit of work in ctx, if any
+func pgsqlConn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(pgsqlTxKey{}).(*gorm.DB); ok {
+		db = tx
+	}
+	return db.WithContext(ctx)
+}
+
+type mongoUnitOfWork struct {
+	client        *mongo.Client
+	transactional bool
+}
+
+// NewMongoUnitOfWork runs units of work in MongoDB transactions. The driver
+// picks the session up from the context, so mongoRepo needs no changes.
+// Transactions need a replica set or sharded cluster; a standalone server is
+// an error unless allowNonTransactional accepts units of work that are not
+// atomic there.
+func NewMongoUnitOfWork(client *mongo.Client, allowNonTransactional bool) (port.UnitOfWork, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return nil, fmt.Errorf("failed to check MongoDB deployment: %w", err)
+	}
+	transactional := hello.SetName != "" || hello.Msg == "isdbgrid"
+	if !transactional {
+		if !allowNonTransactional {
+			return nil, errors.New("MongoDB transactions need a replica set or sharded cluster; set allow_non_transactional to run without them")
+		}
+		log.Println("MongoDB is not a replica set; units of work run without transactions")
+	}
+	return &mongoUnitOfWork{client: client, transactional: transactional}, nil
+}
+
+func (u *mongoUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !u.transactional || mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	session, err := u.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// WithTransaction retries fn on transient errors, so fn must not have
+	// side effects outside the database
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (any, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
@@ -0,0 +1,15 @@
+package domain
+
+// Action names an operation on a resource checked by the authorizer, named "resource:action"
+type Action string
+
+const (
+	ActionBarCreate Action = "bar:create"
+	ActionBarRead   Action = "bar:read"
+	ActionBarUpdate Action = "bar:update"
+	ActionBarDelete Action = "bar:delete"
+	ActionFooCreate Action = "foo:create"
+	ActionFooRead   Action = "foo:read"
+	ActionFooUpdate Action = "foo:update"
+	ActionFooDelete Action = "foo:delete"
+)
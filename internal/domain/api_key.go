@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// APIKeyPrefix starts every API key so leaked keys are easy to recognize
+const APIKeyPrefix = "ggb"
+
+// APIKey represents a long-lived credential for non-interactive clients.
+// Only the SHA-256 hash of the key is stored; the prefix identifies the key.
+// @Description API key information; the key itself is only shown once at creation
+type APIKey struct {
+	ID         string     `json:"id" bson:"_id" gorm:"primaryKey;column:id;type:string" example:"507f1f77bcf86cd799439011" swaggertype:"string" description:"Unique identifier for the API key"`
+	UserID     string     `json:"user_id" bson:"user_id" gorm:"column:user_id;type:string;index" example:"507f1f77bcf86cd799439012" description:"ID of the user owning the key"`
+	Name       string     `json:"name" bson:"name" gorm:"column:name;type:string" example:"nightly-batch" description:"Human readable name of the key"`
+	Prefix     string     `json:"prefix" bson:"prefix" gorm:"column:prefix;type:string;uniqueIndex" example:"3f9a1c2b" description:"Public key prefix used to identify the key"`
+	KeyHash    string     `json:"-" bson:"key_hash" gorm:"column:key_hash;type:string"`
+	Scopes     []string   `json:"scopes" bson:"scopes" gorm:"column:scopes;type:jsonb;serializer:json" example:"bar:read" description:"Permissions the key is limited to"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty" gorm:"column:expires_at" description:"Expiry time; never expires if empty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty" gorm:"column:revoked_at" description:"Time the key was revoked"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bson:"last_used_at,omitempty" gorm:"column:last_used_at" description:"Time the key was last used"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at" gorm:"column:created_at" description:"Creation time"`
+}
+
+func (k *APIKey) SetID(id string) {
+	k.ID = id
+}
+
+// IsActive reports whether the key is neither revoked nor expired at now
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// CreateAPIKeyRequest represents the API key creation payload
+// @Description API key creation request with name, scopes and optional lifetime
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name" binding:"required" example:"nightly-batch" description:"Human readable name of the key"`
+	Scopes    []string `json:"scopes" binding:"required,min=1" example:"bar:read" description:"Permissions to grant; must be held by the caller"`
+	ExpiresIn string   `json:"expires_in" example:"720h" description:"Optional lifetime as a Go duration; the key never expires if empty"`
+}
+
+// CreateAPIKeyResponse represents a newly created API key
+// @Description Created API key including the plaintext key, which is never shown again
+type CreateAPIKeyResponse struct {
+	Key    string  `json:"key" example:"ggb_3f9a1c2b_Qm9vZ2llV29vZ2llQm9vZ2llV29vZ2ll" description:"Plaintext API key for the X-API-Key header"`
+	APIKey *APIKey `json:"api_key" description:"Stored API key metadata"`
+}
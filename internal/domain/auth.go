@@ -11,13 +11,59 @@ type LoginRequest struct {
 // @Description User registration request with email and password
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"newuser@example.com" description:"User email address (must be valid email format)"`
-	Password string `json:"password" binding:"required" example:"securepassword123" description:"User password (minimum 6 characters)"`
+	Password string `json:"password" binding:"required,min=6,max=72" example:"securepassword123" description:"User password (6 to 72 characters)"`
 }
 
-// LoginResponse represents the login response with JWT tokens
-// @Description Successful login response containing user information and JWT tokens
+// RegisterResponse represents the registration response
+// @Description Successful registration response containing the created user's information
+type RegisterResponse struct {
+	ID    string `json:"id" example:"507f1f77bcf86cd799439011" description:"Unique identifier of the created user"`
+	Email string `json:"email" example:"newuser@example.com" description:"Registered user's email address"`
+}
+
+// RefreshTokenRequest represents a request carrying a refresh token
+// @Description Refresh token payload used to obtain new tokens or to log out
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"JWT refresh token issued at login"`
+}
+
+// LoginResponse represents the login response with JWT tokens. For accounts
+// with MFA enabled the tokens are empty and an MFA challenge token is returned
+// instead, to be exchanged at /auth/mfa/verify.
+// @Description Successful login response containing user information and JWT tokens, or an MFA challenge
 type LoginResponse struct {
 	Email        string `json:"email" example:"admin@example.com" description:"Authenticated user's email address"`
-	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"JWT access token for API authentication"`
-	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"JWT refresh token for obtaining new access tokens"`
+	AccessToken  string `json:"access_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"JWT access token for API authentication"`
+	RefreshToken string `json:"refresh_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"JWT refresh token for obtaining new access tokens"`
+	MFARequired  bool   `json:"mfa_required,omitempty" example:"false" description:"Whether a second factor is required to complete the login"`
+	MFAToken     string `json:"mfa_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"Short-lived challenge token for /auth/mfa/verify"`
+}
+
+// ForgotPasswordRequest starts a password reset
+// @Description Email address of the account to reset
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"admin@example.com" description:"User email address"`
+}
+
+// ResetPasswordRequest sets a new password with a mailed reset token
+// @Description Password reset token from the email and the new password
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"Password reset token"`
+	Password string `json:"password" binding:"required,min=6,max=72" example:"newsecurepassword123" description:"New password (6 to 72 characters)"`
+}
+
+// VerifyEmailRequest confirms an email address with a mailed verification token
+// @Description Email verification token from the email
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"Email verification token"`
+}
+
+// ImpersonationResponse carries a token for acting as another user
+// @Description Short-lived access token for acting as another user. It cannot be refreshed.
+type ImpersonationResponse struct {
+	AccessToken string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"Access token for the impersonated user"`
+	TokenType   string `json:"token_type" example:"Bearer" description:"Token type"`
+	ExpiresIn   int64  `json:"expires_in" example:"900" description:"Seconds until the token expires"`
+	UserID      string `json:"user_id" example:"507f1f77bcf86cd799439011" description:"Impersonated user"`
+	ActorID     string `json:"actor_id" example:"507f1f77bcf86cd799439012" description:"Administrator the token was issued to"`
 }
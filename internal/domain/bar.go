@@ -7,4 +7,9 @@ type Bar struct {
 	Name        string `json:"name" bson:"name;type:string" gorm:"column:name;type:string" example:"Sample Bar" validate:"required" description:"Name of the bar item (required)"`
 	Description string `json:"description" bson:"description;type:string" gorm:"column:description;type:string" example:"This is a sample bar description" description:"Detailed description of the bar item"`
 	Status      string `json:"status" bson:"status;type:string" gorm:"column:status;type:string" example:"active" enums:"active,inactive" description:"Current status of the bar item"`
+	OwnerID     string `json:"owner_id" bson:"owner_id" gorm:"column:owner_id;type:string" example:"507f1f77bcf86cd799439012" description:"ID of the user who created the bar item"`
+}
+
+func (b *Bar) SetID(id string) {
+	b.ID = id
 }
@@ -0,0 +1,7 @@
+package domain
+
+// Entity is a domain type stored by a generic repository, which assigns the
+// ID of new entities
+type Entity interface {
+	SetID(id string)
+}
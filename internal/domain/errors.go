@@ -0,0 +1,80 @@
+package domain
+
+import "errors"
+
+// Error kinds shared by every layer. Repositories and services return errors
+// matching one of these with errors.Is, and handlers derive the HTTP status
+// from the kind alone. Errors matching none of them are internal errors.
+var (
+	// ErrBadRequest is returned for requests that cannot be parsed
+	ErrBadRequest = errors.New("bad request")
+	// ErrValidation is returned for malformed or missing input
+	ErrValidation = errors.New("validation failed")
+	// ErrUnauthorized is returned when the caller's credentials are missing or invalid
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is returned when the caller is not allowed to perform an action
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound is returned when the requested entity does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when the request clashes with existing state
+	ErrConflict = errors.New("conflict")
+	// ErrTooManyRequests is returned when the caller must wait before retrying
+	ErrTooManyRequests = errors.New("too many requests")
+	// ErrUpstream is returned when a service this one depends on, such as an
+	// external identity provider, fails
+	ErrUpstream = errors.New("upstream service failed")
+)
+
+// Error is an error of one of the kinds above with a message for the client.
+// The cause, if any, is kept for errors.Is/As and logs but is not part of
+// the message. Validation errors may name the offending fields.
+type Error struct {
+	Kind    error
+	Message string
+	Cause   error
+	Fields  []FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Cause}
+}
+
+// WrapError classifies cause as an error of the given kind with a message
+// for the client
+func WrapError(kind error, message string, cause error) error {
+	return &Error{Kind: kind, Message: message, Cause: cause}
+}
+
+// NewValidationError returns an ErrValidation error with the given message
+// and, optionally, the invalid fields
+func NewValidationError(message string, fields ...FieldError) error {
+	return &Error{Kind: ErrValidation, Message: message, Fields: fields}
+}
+
+// NewUnauthorizedError returns an ErrUnauthorized error with the given message
+func NewUnauthorizedError(message string) error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+// NewForbiddenError returns an ErrForbidden error with the given message
+func NewForbiddenError(message string) error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
+
+// NewNotFoundError returns an ErrNotFound error with the given message
+func NewNotFoundError(message string) error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+// NewConflictError returns an ErrConflict error with the given message
+func NewConflictError(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "context"
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	UserID string
+	Roles  []string
+	// Scopes restricts the caller to these permissions when authenticated
+	// with an API key or an OAuth2 client token; nil means no restriction
+	// beyond the roles
+	Scopes []string
+	// ClientID is the OAuth2 client the token was issued to, if any
+	ClientID string
+	// SessionID is the login session the access token belongs to, if any
+	SessionID string
+	// ActorID is the administrator acting as UserID with an impersonation
+	// token, if any
+	ActorID string
+}
+
+// IsClient reports whether the caller is an OAuth2 client acting on its own
+// behalf (client_credentials) rather than on behalf of a user
+func (i *Identity) IsClient() bool {
+	return i.ClientID != "" && i.UserID == i.ClientID
+}
+
+// IsImpersonated reports whether an administrator is acting as the user
+func (i *Identity) IsImpersonated() bool {
+	return i.ActorID != ""
+}
+
+// IsDelegated reports whether the caller is not the user in person but an
+// API key, an OAuth2 token or an administrator impersonating the user. Such
+// callers may not manage the user's credentials.
+func (i *Identity) IsDelegated() bool {
+	return i.Scopes != nil || i.IsImpersonated()
+}
+
+// Can reports whether the identity's roles grant the permission and, for API
+// keys and OAuth2 tokens, whether the granted scopes include it. Clients
+// acting on their own behalf have no roles and are limited by scopes alone.
+func (i *Identity) Can(permission Permission) bool {
+	if !i.IsClient() && !HasPermission(i.Roles, permission) {
+		return false
+	}
+	if i.Scopes == nil {
+		return true
+	}
+	for _, scope := range i.Scopes {
+		if Permission(scope) == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the caller's identity
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the caller's identity, or nil for anonymous calls
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
@@ -0,0 +1,62 @@
+package domain
+
+// Limits on the number of items a list request returns
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// FilterOperator compares a field with the value of a list filter
+type FilterOperator string
+
+const (
+	FilterEq       FilterOperator = "eq"
+	FilterNe       FilterOperator = "ne"
+	FilterGt       FilterOperator = "gt"
+	FilterGte      FilterOperator = "gte"
+	FilterLt       FilterOperator = "lt"
+	FilterLte      FilterOperator = "lte"
+	FilterIn       FilterOperator = "in"
+	FilterContains FilterOperator = "contains"
+)
+
+// FilterOperators lists the operators list filters accept
+var FilterOperators = []FilterOperator{FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterIn, FilterContains}
+
+// FieldFilter restricts a list to items whose field compares to the value.
+// The value of FilterIn is a slice of the accepted values.
+type FieldFilter struct {
+	Field    string
+	Operator FilterOperator
+	Value    any
+}
+
+// SortField orders a list by a field
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListQuery selects a page of a list. Fields are named by their JSON names.
+// Pages are addressed either by Offset or by the Cursor of the previous page.
+type ListQuery struct {
+	Filters []FieldFilter
+	Sort    []SortField
+	Limit   int
+	Offset  int
+	Cursor  string
+}
+
+// Page is one page of a list
+// @Description A page of items with the total number of matching items and the cursor of the next page
+type Page[T any] struct {
+	Items      []T    `json:"items" description:"Items of this page"`
+	Total      int64  `json:"total" example:"42" description:"Number of items matching the filters across all pages"`
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoibmFtZSxpZCIsInYiOlsiQiIsIjIiXX0" description:"Cursor of the next page; empty on the last page"`
+}
+
+// Fields that list requests may filter and sort by
+var (
+	FooListFields = []string{"id", "name"}
+	BarListFields = []string{"id", "name", "description", "status", "owner_id"}
+)
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// LoginAttempts tracks recent login attempts of an account or a client IP
+// that did not succeed
+type LoginAttempts struct {
+	Count       int       `json:"count"`
+	LastAttempt time.Time `json:"last_attempt"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// LoginThrottlePolicy configures brute-force protection for password logins
+type LoginThrottlePolicy struct {
+	// MaxAttempts is the number of failures that locks an account
+	MaxAttempts int
+	// IPMaxAttempts is the number of failures that locks a client IP
+	IPMaxAttempts int
+	// Window is how long a failure counts towards the limits
+	Window time.Duration
+	// LockoutDuration is how long a locked account or IP must wait
+	LockoutDuration time.Duration
+	// BaseDelay is the wait after the first failure of an account; it doubles
+	// with every further failure up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// LoginThrottledError is returned when a login is refused because of
+// previous failed attempts
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return "too many login attempts, try again later"
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyRequests
+}
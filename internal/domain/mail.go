@@ -0,0 +1,8 @@
+package domain
+
+// EmailMessage is a plain-text email sent through a port.Mailer
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
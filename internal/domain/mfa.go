@@ -0,0 +1,23 @@
+package domain
+
+// MFAEnrollResponse contains what the user needs to set up an authenticator
+// app. The recovery codes are shown only once.
+// @Description TOTP enrollment data
+type MFAEnrollResponse struct {
+	Secret        string   `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP" description:"Base32 TOTP secret for manual entry"`
+	OTPAuthURI    string   `json:"otpauth_uri" example:"otpauth://totp/go-gin-boilerplate:admin@example.com?secret=JBSWY3DPEHPK3PXP&issuer=go-gin-boilerplate" description:"otpauth URI to render as a QR code"`
+	RecoveryCodes []string `json:"recovery_codes" example:"3f9a1-c2b4d" description:"Single-use codes for when the authenticator is unavailable"`
+}
+
+// MFACodeRequest carries a TOTP or recovery code
+// @Description TOTP code from the authenticator app, or a recovery code
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456" description:"TOTP or recovery code"`
+}
+
+// MFAVerifyRequest completes a login that requires a second factor
+// @Description MFA challenge token from login and a TOTP or recovery code
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." description:"Challenge token returned by login"`
+	Code     string `json:"code" binding:"required" example:"123456" description:"TOTP or recovery code"`
+}
@@ -0,0 +1,143 @@
+package domain
+
+import "time"
+
+// OAuth2 grant types supported by the token endpoint
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeAuthorizationCode = "authorization_code"
+)
+
+// OAuth2 error codes (RFC 6749 section 5.2). The token endpoint reports the
+// code of the sentinel an error wraps; elsewhere they are validation errors.
+var (
+	ErrOAuthInvalidRequest       = NewValidationError("invalid_request")
+	ErrOAuthInvalidClient        = NewValidationError("invalid_client")
+	ErrOAuthInvalidGrant         = NewValidationError("invalid_grant")
+	ErrOAuthUnauthorizedClient   = NewValidationError("unauthorized_client")
+	ErrOAuthUnsupportedGrantType = NewValidationError("unsupported_grant_type")
+	ErrOAuthInvalidScope         = NewValidationError("invalid_scope")
+)
+
+// OAuthClient represents an application registered to obtain tokens from this
+// service. Confidential clients authenticate with a secret of which only the
+// SHA-256 hash is stored; public clients have no secret and must use PKCE.
+// @Description OAuth2 client information; the secret is only shown once at registration
+type OAuthClient struct {
+	ID           string    `json:"client_id" bson:"_id" gorm:"primaryKey;column:id;type:string" example:"9f86d081884c7d659a2feaa0c55ad015" swaggertype:"string" description:"Client identifier"`
+	Name         string    `json:"name" bson:"name" gorm:"column:name;type:string" example:"billing-service" description:"Human readable name of the client"`
+	SecretHash   string    `json:"-" bson:"secret_hash" gorm:"column:secret_hash;type:string"`
+	Public       bool      `json:"public" bson:"public" gorm:"column:public" example:"false" description:"Public clients have no secret and must use PKCE"`
+	RedirectURIs []string  `json:"redirect_uris" bson:"redirect_uris" gorm:"column:redirect_uris;type:jsonb;serializer:json" example:"https://app.example.com/callback" description:"Allowed redirect URIs for the authorization_code grant"`
+	GrantTypes   []string  `json:"grant_types" bson:"grant_types" gorm:"column:grant_types;type:jsonb;serializer:json" example:"client_credentials" description:"Grant types the client may use"`
+	Scopes       []string  `json:"scopes" bson:"scopes" gorm:"column:scopes;type:jsonb;serializer:json" example:"bar:read" description:"Maximum scopes the client may be granted"`
+	CreatedBy    string    `json:"created_by" bson:"created_by" gorm:"column:created_by;type:string" description:"ID of the user who registered the client"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at" gorm:"column:created_at" description:"Registration time"`
+}
+
+func (c *OAuthClient) SetID(id string) {
+	c.ID = id
+}
+
+// AllowsGrant reports whether the client is registered for the grant type
+func (c *OAuthClient) AllowsGrant(grantType string) bool {
+	for _, g := range c.GrantTypes {
+		if g == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRedirectURI reports whether uri exactly matches a registered redirect URI
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	for _, u := range c.RedirectURIs {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterOAuthClientRequest represents the client registration payload
+// @Description OAuth2 client registration request
+type RegisterOAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required" example:"billing-service" description:"Human readable name of the client"`
+	Public       bool     `json:"public" example:"false" description:"Register a public client without secret"`
+	RedirectURIs []string `json:"redirect_uris" example:"https://app.example.com/callback" description:"Redirect URIs, required for authorization_code"`
+	GrantTypes   []string `json:"grant_types" binding:"required,min=1" example:"client_credentials" description:"Grant types: client_credentials, authorization_code"`
+	Scopes       []string `json:"scopes" binding:"required,min=1" example:"bar:read" description:"Permissions the client may be granted"`
+}
+
+// RegisterOAuthClientResponse contains the new client and, for confidential
+// clients, the plaintext secret, which cannot be retrieved again
+// @Description OAuth2 client registration response
+type RegisterOAuthClientResponse struct {
+	ClientSecret string       `json:"client_secret,omitempty" example:"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8" description:"Plaintext client secret, shown only once"`
+	Client       *OAuthClient `json:"client"`
+}
+
+// AuthorizeRequest represents the query of an authorization request
+type AuthorizeRequest struct {
+	ResponseType        string `form:"response_type" binding:"required"`
+	ClientID            string `form:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" binding:"required"`
+	Scope               string `form:"scope"`
+	State               string `form:"state"`
+	CodeChallenge       string `form:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method"`
+}
+
+// AuthorizationCode is what an issued authorization code stands for until it
+// is redeemed at the token endpoint
+type AuthorizationCode struct {
+	ClientID      string   `json:"client_id"`
+	UserID        string   `json:"user_id"`
+	RedirectURI   string   `json:"redirect_uri"`
+	Scopes        []string `json:"scopes"`
+	CodeChallenge string   `json:"code_challenge,omitempty"`
+}
+
+// TokenRequest represents a form-encoded token endpoint request. Client
+// credentials may also be sent with HTTP Basic authentication.
+type TokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Scope        string `form:"scope"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+}
+
+// TokenResponse is the successful token endpoint response (RFC 6749 section 5.1)
+// @Description OAuth2 access token response
+type TokenResponse struct {
+	AccessToken string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int64  `json:"expires_in" example:"900"`
+	Scope       string `json:"scope,omitempty" example:"bar:read"`
+}
+
+// OAuthErrorResponse is the token endpoint error response (RFC 6749 section 5.2)
+// @Description OAuth2 error response
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_client"`
+	ErrorDescription string `json:"error_description,omitempty" example:"client authentication failed"`
+}
+
+// IntrospectionResponse describes a token (RFC 7662 section 2.2). Only
+// Active is set for inactive tokens.
+// @Description OAuth2 token introspection response
+type IntrospectionResponse struct {
+	Active    bool     `json:"active" example:"true"`
+	Scope     string   `json:"scope,omitempty" example:"bar:read"`
+	ClientID  string   `json:"client_id,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	TokenType string   `json:"token_type,omitempty" example:"Bearer"`
+	Exp       int64    `json:"exp,omitempty" example:"1735689600"`
+	Iat       int64    `json:"iat,omitempty" example:"1735688700"`
+	Sub       string   `json:"sub,omitempty" example:"507f1f77bcf86cd799439011"`
+	Aud       []string `json:"aud,omitempty" example:"go-gin-boilerplate"`
+	Iss       string   `json:"iss,omitempty" example:"go-gin-boilerplate"`
+	Jti       string   `json:"jti,omitempty" example:"3f9a1c2b4d5e6f70"`
+}
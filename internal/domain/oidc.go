@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+var (
+	// ErrOIDCProviderNotFound is returned for provider names that are not configured
+	ErrOIDCProviderNotFound = NewNotFoundError("oidc provider not found")
+	// ErrOIDCStateInvalid is returned when a callback's state is unknown, expired or already used
+	ErrOIDCStateInvalid = NewUnauthorizedError("invalid or expired oidc state")
+)
+
+// OIDCAuthState is kept between redirecting to the provider and its callback.
+// It is looked up by the opaque state parameter and consumed exactly once.
+type OIDCAuthState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// ExternalIdentity links an account at an external identity provider to a
+// local user. Its ID is derived from the provider and subject, which makes the
+// pair unique.
+type ExternalIdentity struct {
+	ID        string    `json:"id" bson:"_id" gorm:"primaryKey;column:id;type:string"`
+	UserID    string    `json:"user_id" bson:"user_id" gorm:"column:user_id;type:string;index"`
+	Provider  string    `json:"provider" bson:"provider" gorm:"column:provider;type:string;uniqueIndex:idx_external_identity_provider_subject"`
+	Subject   string    `json:"subject" bson:"subject" gorm:"column:subject;type:string;uniqueIndex:idx_external_identity_provider_subject"`
+	Email     string    `json:"email" bson:"email" gorm:"column:email;type:string"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at" gorm:"column:created_at"`
+}
+
+func (e *ExternalIdentity) SetID(id string) {
+	e.ID = id
+}
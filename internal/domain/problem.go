@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Machine-readable error codes carried by problem responses
+const (
+	CodeBadRequest      = "bad_request"
+	CodeValidation      = "validation_failed"
+	CodeUnauthorized    = "unauthorized"
+	CodeForbidden       = "forbidden"
+	CodeNotFound        = "not_found"
+	CodeConflict        = "conflict"
+	CodeTooManyRequests = "too_many_requests"
+	CodeUpstream        = "upstream_failed"
+	CodeInternal        = "internal_error"
+)
+
+// Problem is the error response format of every endpoint, following RFC 7807
+// @Description Error response in application/problem+json format (RFC 7807)
+type Problem struct {
+	Type     string       `json:"type" example:"about:blank" description:"URI identifying the problem type"`
+	Title    string       `json:"title" example:"Not Found" description:"Short summary of the problem type"`
+	Status   int          `json:"status" example:"404" description:"HTTP status code"`
+	Detail   string       `json:"detail,omitempty" example:"bar not found" description:"Explanation of this occurrence of the problem"`
+	Instance string       `json:"instance,omitempty" example:"/api/v1/bar/507f1f77bcf86cd799439011" description:"Path of the request that caused the problem"`
+	Code     string       `json:"code" example:"not_found" description:"Machine-readable error code"`
+	Errors   []FieldError `json:"errors,omitempty" description:"Invalid fields of a validation problem"`
+}
+
+// FieldError describes why a single request field is invalid
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Message string `json:"message" example:"must be a valid email address"`
+}
+
+// NewProblem creates a problem of the generic type for the given status
+func NewProblem(status int, code string, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// ProblemFromError creates the problem for an error, with the status and
+// code of its kind. Errors of no known kind are internal server errors.
+func ProblemFromError(err error) Problem {
+	status, code := http.StatusInternalServerError, CodeInternal
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		status, code = http.StatusBadRequest, CodeBadRequest
+	case errors.Is(err, ErrValidation):
+		status, code = http.StatusBadRequest, CodeValidation
+	case errors.Is(err, ErrUnauthorized):
+		status, code = http.StatusUnauthorized, CodeUnauthorized
+	case errors.Is(err, ErrForbidden):
+		status, code = http.StatusForbidden, CodeForbidden
+	case errors.Is(err, ErrNotFound):
+		status, code = http.StatusNotFound, CodeNotFound
+	case errors.Is(err, ErrConflict):
+		status, code = http.StatusConflict, CodeConflict
+	case errors.Is(err, ErrTooManyRequests):
+		status, code = http.StatusTooManyRequests, CodeTooManyRequests
+	case errors.Is(err, ErrUpstream):
+		status, code = http.StatusBadGateway, CodeUpstream
+	}
+
+	problem := NewProblem(status, code, err.Error())
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		problem.Errors = domainErr.Fields
+	}
+	return problem
+}
@@ -1,6 +1,7 @@
 package domain
 
-// BaseResponse represents the standard API response format
+// BaseResponse represents the standard API response format for successful
+// requests; errors are reported as a Problem
 // @Description Standard API response format with generic data type
 type BaseResponse[T any] struct {
 	Message string `json:"message" example:"success" description:"Response message indicating the result of the operation"`
@@ -29,11 +30,3 @@ func SuccessResponseWithMessage[T any](message string, data T) BaseResponse[T] {
 		Data:    data,
 	}
 }
-
-// ErrorResponse creates an error response (data will be nil)
-func ErrorResponse(message string) BaseResponse[interface{}] {
-	return BaseResponse[interface{}]{
-		Message: message,
-		Data:    nil,
-	}
-}
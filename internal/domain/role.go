@@ -0,0 +1,59 @@
+package domain
+
+// Roles assigned to users
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Permission is an action a role may perform on a resource, named "resource:action"
+type Permission string
+
+const (
+	PermissionBarRead       Permission = "bar:read"
+	PermissionBarWrite      Permission = "bar:write"
+	PermissionBarDelete     Permission = "bar:delete"
+	PermissionFooWrite      Permission = "foo:write"
+	PermissionFooDelete     Permission = "foo:delete"
+	PermissionUsersManage   Permission = "users:manage"
+	PermissionClientsManage Permission = "clients:manage"
+	// PermissionUsersImpersonate allows acting as another user for support
+	PermissionUsersImpersonate Permission = "users:impersonate"
+)
+
+// RolePermissions maps each role to the permissions it grants
+var RolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermissionBarRead, PermissionBarWrite, PermissionBarDelete,
+		PermissionFooWrite, PermissionFooDelete,
+		PermissionUsersManage, PermissionClientsManage, PermissionUsersImpersonate,
+	},
+	RoleUser: {
+		PermissionBarRead, PermissionBarWrite,
+		PermissionFooWrite,
+	},
+}
+
+// HasRole reports whether roles contains any of the wanted roles
+func HasRole(roles []string, wanted ...string) bool {
+	for _, role := range roles {
+		for _, w := range wanted {
+			if role == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether any of the roles grants the permission
+func HasPermission(roles []string, permission Permission) bool {
+	for _, role := range roles {
+		for _, p := range RolePermissions[role] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ErrSessionNotFound is returned when a session is unknown, expired or owned
+// by another user
+var ErrSessionNotFound = NewNotFoundError("session not found")
+
+// Session is a login of a user on one device. Its ID is the refresh token
+// family ID shared by all tokens issued for the login.
+// @Description Active login session of the current user
+type Session struct {
+	ID         string    `json:"id" example:"9f86d081884c7d659a2feaa0c55ad015" description:"Session identifier"`
+	UserID     string    `json:"-"`
+	Device     string    `json:"device" example:"Chrome on macOS" description:"Device derived from the user agent"`
+	IP         string    `json:"ip" example:"203.0.113.7" description:"IP address the session was last used from"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)" description:"User agent the session was last used from"`
+	CreatedAt  time.Time `json:"created_at" description:"Time of the login"`
+	LastUsedAt time.Time `json:"last_used_at" description:"Time tokens were last issued for the session"`
+	ExpiresAt  time.Time `json:"expires_at" description:"Time the session ends unless refreshed"`
+	Current    bool      `json:"current" example:"true" description:"Whether the request was made with this session"`
+}
+
+// ClientInfo describes the client a request came from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	Device    string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo returns a copy of ctx carrying the client's details
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client's details, or the zero value when
+// unknown
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+var (
+	// ErrRefreshTokenNotFound is returned when a refresh token is unknown or expired
+	ErrRefreshTokenNotFound = NewUnauthorizedError("refresh token not found")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again
+	ErrRefreshTokenReused = NewUnauthorizedError("refresh token reuse detected")
+)
+
+// RefreshTokenRecord tracks an issued refresh token for rotation and reuse detection
+type RefreshTokenRecord struct {
+	JTI       string    `json:"jti"`
+	FamilyID  string    `json:"family_id"`
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+// User represents a registered account in the system
+// @Description User account information; the password hash is never serialized
+type User struct {
+	ID            string   `json:"id" bson:"_id" gorm:"primaryKey;column:id;type:string" example:"507f1f77bcf86cd799439011" swaggertype:"string" description:"Unique identifier for the user"`
+	Email         string   `json:"email" bson:"email" gorm:"column:email;type:string;uniqueIndex" example:"admin@example.com" description:"User email address"`
+	EmailVerified bool     `json:"email_verified" bson:"email_verified" gorm:"column:email_verified" example:"true" description:"Whether the user confirmed the email address"`
+	PasswordHash  string   `json:"-" bson:"password_hash" gorm:"column:password_hash;type:string"`
+	Roles         []string `json:"roles" bson:"roles" gorm:"column:roles;type:jsonb;serializer:json" example:"user" description:"Roles granted to the user"`
+	MFAEnabled    bool     `json:"mfa_enabled" bson:"mfa_enabled" gorm:"column:mfa_enabled" example:"false" description:"Whether login requires a TOTP code"`
+	// MFASecret is set at enrollment and only used once MFAEnabled is confirmed
+	MFASecret string `json:"-" bson:"mfa_secret" gorm:"column:mfa_secret;type:string"`
+	// MFARecoveryCodes holds SHA-256 hashes of the unused recovery codes
+	MFARecoveryCodes []string `json:"-" bson:"mfa_recovery_codes" gorm:"column:mfa_recovery_codes;type:jsonb;serializer:json"`
+	// MFALastStep is the TOTP time step of the last accepted code, to prevent replays
+	MFALastStep int64 `json:"-" bson:"mfa_last_step" gorm:"column:mfa_last_step"`
+}
+
+func (u *User) SetID(id string) {
+	u.ID = id
+}
+
+// EffectiveRoles returns the user's roles, defaulting to the regular user role
+// for accounts created before roles existed
+func (u *User) EffectiveRoles() []string {
+	if len(u.Roles) == 0 {
+		return []string{RoleUser}
+	}
+	return u.Roles
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	authSvc port.AuthService
+}
+
+func NewAdminHandler(authSvc port.AuthService) *AdminHandler {
+	return &AdminHandler{authSvc: authSvc}
+}
+
+// RevokeUserTokens revokes every token issued to a user
+// @Summary      Revoke all tokens of a user
+// @Description  Invalidate every access and refresh token and every API key issued to the given user so far. Requires administrator privileges.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID" example("507f1f77bcf86cd799439011")
+// @Success      200 {object} domain.Response{data=string} "Tokens revoked successfully"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Admin privileges required"
+// @Failure      404 {object} domain.Problem "User not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /admin/users/{id}/revoke-tokens [post]
+func (h *AdminHandler) RevokeUserTokens(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.authSvc.RevokeUserTokens(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Tokens revoked successfully", ""))
+}
+
+// Impersonate issues a token for acting as another user
+// @Summary      Impersonate a user
+// @Description  Issue a short-lived access token for acting as the given user, e.g. to reproduce a support issue. The token names the administrator in its act claim and every request made with it is logged. Administrators cannot be impersonated. Requires the users:impersonate permission.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID" example("507f1f77bcf86cd799439011")
+// @Success      200 {object} domain.Response{data=domain.ImpersonationResponse} "Impersonation token issued"
+// @Failure      400 {object} domain.Problem "Bad request - Cannot impersonate yourself"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission, delegated credentials or target is an administrator"
+// @Failure      404 {object} domain.Problem "User not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /admin/users/{id}/impersonate [post]
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	resp, err := h.authSvc.Impersonate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Impersonation token issued", resp))
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/handler"
+	"go-gin-boilerplate/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAdminRoutes(router *gin.RouterGroup, adminHandler *handler.AdminHandler) {
+	router.POST("/users/:id/revoke-tokens", adminHandler.RevokeUserTokens)
+	router.POST("/users/:id/impersonate", middleware.RequirePermission(domain.PermissionUsersImpersonate), adminHandler.Impersonate)
+}
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"go-gin-boilerplate/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAPIKeyRoutes(router *gin.RouterGroup, apiKeyHandler *handler.APIKeyHandler) {
+	router.POST("/", apiKeyHandler.Create)
+	router.GET("/", apiKeyHandler.List)
+	router.DELETE("/:id", apiKeyHandler.Revoke)
+}
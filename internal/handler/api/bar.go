@@ -1,15 +1,17 @@
 package api
 
 import (
+	"go-gin-boilerplate/internal/domain"
 	"go-gin-boilerplate/internal/handler"
+	"go-gin-boilerplate/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterBarRoutes(router *gin.RouterGroup, barHandler *handler.BarHandler) {
-	router.POST("/", barHandler.Create)
-	router.GET("/", barHandler.GetAll)
-	router.GET("/:id", barHandler.GetByID)
-	router.PUT("/:id", barHandler.UpdateByID)
-	router.DELETE("/:id", barHandler.DeleteByID)
+	router.POST("/", middleware.RequirePermission(domain.PermissionBarWrite), barHandler.Create)
+	router.GET("/", middleware.RequirePermission(domain.PermissionBarRead), barHandler.GetAll)
+	router.GET("/:id", middleware.RequirePermission(domain.PermissionBarRead), barHandler.GetByID)
+	router.PUT("/:id", middleware.RequirePermission(domain.PermissionBarWrite), barHandler.UpdateByID)
+	router.DELETE("/:id", middleware.RequirePermission(domain.PermissionBarDelete), barHandler.DeleteByID)
 }
@@ -1,15 +1,19 @@
 package api
 
 import (
+	"go-gin-boilerplate/internal/domain"
 	"go-gin-boilerplate/internal/handler"
+	"go-gin-boilerplate/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterFooRoutes(router *gin.RouterGroup, fooHandler *handler.FooHandler) {
-	router.POST("/", fooHandler.Create)
+// RegisterFooRoutes keeps reads public; writes require authentication and a
+// permission granted by the caller's roles
+func RegisterFooRoutes(router *gin.RouterGroup, fooHandler *handler.FooHandler, authMiddleware gin.HandlerFunc) {
+	router.POST("/", authMiddleware, middleware.RequirePermission(domain.PermissionFooWrite), fooHandler.Create)
 	router.GET("/", fooHandler.GetAll)
 	router.GET("/:id", fooHandler.GetByID)
-	router.PUT("/:id", fooHandler.UpdateByID)
-	router.DELETE("/:id", fooHandler.DeleteByID)
+	router.PUT("/:id", authMiddleware, middleware.RequirePermission(domain.PermissionFooWrite), fooHandler.UpdateByID)
+	router.DELETE("/:id", authMiddleware, middleware.RequirePermission(domain.PermissionFooDelete), fooHandler.DeleteByID)
 }
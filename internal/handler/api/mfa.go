@@ -0,0 +1,16 @@
+package api
+
+import (
+	"go-gin-boilerplate/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMFARoutes registers MFA routes below /auth/mfa. Verification is the
+// second login step and therefore public; managing MFA needs a signed-in user.
+func RegisterMFARoutes(router *gin.RouterGroup, mfaHandler *handler.MFAHandler, authMiddleware gin.HandlerFunc) {
+	router.POST("/verify", mfaHandler.Verify)
+	router.POST("/enroll", authMiddleware, mfaHandler.Enroll)
+	router.POST("/activate", authMiddleware, mfaHandler.Activate)
+	router.POST("/disable", authMiddleware, mfaHandler.Disable)
+}
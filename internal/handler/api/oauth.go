@@ -0,0 +1,23 @@
+package api
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/handler"
+	"go-gin-boilerplate/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOAuthRoutes registers the OAuth2 endpoints. Token and introspection
+// authenticate the client themselves; authorizing a client needs a signed-in
+// user and managing clients the clients:manage permission.
+func RegisterOAuthRoutes(router *gin.RouterGroup, oauthHandler *handler.OAuthHandler, authMiddleware gin.HandlerFunc) {
+	router.POST("/token", oauthHandler.Token)
+	router.POST("/introspect", oauthHandler.Introspect)
+	router.GET("/authorize", authMiddleware, oauthHandler.Authorize)
+
+	clients := router.Group("/clients", authMiddleware, middleware.RequirePermission(domain.PermissionClientsManage))
+	clients.POST("/", oauthHandler.RegisterClient)
+	clients.GET("/", oauthHandler.ListClients)
+	clients.DELETE("/:id", oauthHandler.DeleteClient)
+}
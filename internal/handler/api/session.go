@@ -0,0 +1,12 @@
+package api
+
+import (
+	"go-gin-boilerplate/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSessionRoutes(router *gin.RouterGroup, sessionHandler *handler.SessionHandler) {
+	router.GET("/", sessionHandler.List)
+	router.DELETE("/:id", sessionHandler.Revoke)
+}
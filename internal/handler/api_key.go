@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeySvc port.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeySvc port.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeySvc: apiKeySvc}
+}
+
+// Create creates a new API key for the current user
+// @Summary      Create an API key
+// @Description  Create a scoped API key for the current user. The plaintext key is returned only once. Requires a bearer token.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        apiKey body domain.CreateAPIKeyRequest true "API key creation data"
+// @Success      201 {object} domain.Response{data=domain.CreateAPIKeyResponse} "Successfully created API key"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid input data"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - API keys cannot create API keys"
+// @Router       /api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req domain.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	created, err := h.apiKeySvc.Create(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.SuccessResponseWithMessage("API key created successfully", created))
+}
+
+// List lists the current user's API keys
+// @Summary      List API keys
+// @Description  Retrieve the current user's API keys without their secrets
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Success      200 {object} domain.Response{data=[]domain.APIKey} "Successfully retrieved API keys"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	apiKeys, err := h.apiKeySvc.List(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("API keys retrieved successfully", apiKeys))
+}
+
+// Revoke revokes one of the current user's API keys
+// @Summary      Revoke an API key
+// @Description  Revoke one of the current user's API keys by ID
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path string true "API key ID" example("507f1f77bcf86cd799439011")
+// @Success      200 {object} domain.Response{data=string} "Successfully revoked API key"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      404 {object} domain.Problem "API key not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.apiKeySvc.Revoke(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("API key revoked successfully", ""))
+}
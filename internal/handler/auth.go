@@ -1,45 +1,273 @@
 package handler
 
 import (
+	"errors"
 	"go-gin-boilerplate/internal/domain"
 	"go-gin-boilerplate/internal/port"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
-	authSvc port.AuthService
+	authSvc    port.AuthService
+	accountSvc port.AccountService
+	throttler  port.LoginThrottler
 }
 
-func NewAuthHandler(authSvc port.AuthService) *AuthHandler {
-	return &AuthHandler{authSvc: authSvc}
+func NewAuthHandler(authSvc port.AuthService, accountSvc port.AccountService, throttler port.LoginThrottler) *AuthHandler {
+	return &AuthHandler{authSvc: authSvc, accountSvc: accountSvc, throttler: throttler}
 }
 
 // Login authenticates a user and returns access tokens
 // @Summary      User authentication
-// @Description  Authenticate user with email and password, returns JWT access and refresh tokens
+// @Description  Authenticate user with email and password, returns JWT access and refresh tokens. Users with MFA enabled get mfa_required and an mfa_token to complete the login at /auth/mfa/verify. Repeated failures slow down and then temporarily lock the account and the client IP.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        loginRequest body domain.LoginRequest true "User login credentials" example({"email": "admin@example.com", "password": "password123"})
 // @Success      200 {object} domain.Response{data=domain.LoginResponse} "Login successful - Returns access and refresh tokens"
-// @Failure      400 {object} domain.Response "Bad request - Invalid JSON format or missing required fields"
-// @Failure      401 {object} domain.Response "Unauthorized - Invalid email or password"
-// @Failure      500 {object} domain.Response "Internal server error - Failed to generate tokens"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid JSON format or missing required fields"
+// @Failure      401 {object} domain.Problem "Unauthorized - Invalid email or password"
+// @Failure      429 {object} domain.Problem "Too many requests - Too many failed attempts, retry after the Retry-After header"
+// @Header       429 {integer} Retry-After "Seconds to wait before the next attempt"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var loginReq domain.LoginRequest
 	if err := c.ShouldBindJSON(&loginReq); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse(err.Error()))
+		_ = c.Error(bindError(err))
 		return
 	}
 
-	loginResp, err := h.authSvc.Login(loginReq)
+	ctx := c.Request.Context()
+	clientIP := c.ClientIP()
+	if err := h.throttler.Attempt(ctx, loginReq.Email, clientIP); err != nil {
+		setRetryAfter(c, err)
+		_ = c.Error(err)
+		return
+	}
+
+	loginResp, err := h.authSvc.Login(ctx, loginReq)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, domain.ErrorResponse(err.Error()))
+		// Only rejected credentials count as a failed attempt, not outages
+		if !errors.Is(err, domain.ErrUnauthorized) {
+			if err := h.throttler.Forgive(ctx, loginReq.Email, clientIP); err != nil {
+				_ = c.Error(err)
+			}
+		}
+		_ = c.Error(err)
 		return
 	}
 
+	// A login waiting for its second factor has not succeeded yet; MFA
+	// verification counts its own attempt
+	if loginResp.MFARequired {
+		err = h.throttler.Forgive(ctx, loginReq.Email, clientIP)
+	} else {
+		err = h.throttler.RecordSuccess(ctx, loginReq.Email, clientIP)
+	}
+	if err != nil {
+		_ = c.Error(err)
+	}
+
 	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Login successful", loginResp))
 }
+
+// Register creates a new user account
+// @Summary      User registration
+// @Description  Register a new user with email and password. A verification link is emailed to the new user.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        registerRequest body domain.RegisterRequest true "User registration data"
+// @Success      201 {object} domain.Response{data=domain.RegisterResponse} "Registration successful"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid JSON format or missing required fields"
+// @Failure      409 {object} domain.Problem "Conflict - Email already registered"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var registerReq domain.RegisterRequest
+	if err := c.ShouldBindJSON(&registerReq); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	registerResp, err := h.authSvc.Register(c.Request.Context(), registerReq)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// The account exists either way; a failed email can be resent later
+	if err := h.accountSvc.SendVerificationEmail(c.Request.Context(), registerResp.ID); err != nil {
+		_ = c.Error(err)
+	}
+
+	c.JSON(http.StatusCreated, domain.SuccessResponseWithMessage("Registration successful", registerResp))
+}
+
+// RefreshToken exchanges a refresh token for a new token pair
+// @Summary      Refresh tokens
+// @Description  Exchange a valid refresh token for a new access and refresh token pair
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        refreshRequest body domain.RefreshTokenRequest true "Refresh token"
+// @Success      200 {object} domain.Response{data=domain.LoginResponse} "Tokens refreshed successfully"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid JSON format or missing required fields"
+// @Failure      401 {object} domain.Problem "Unauthorized - Invalid or expired refresh token"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var refreshReq domain.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&refreshReq); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	loginResp, err := h.authSvc.RefreshToken(c.Request.Context(), refreshReq.RefreshToken)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Tokens refreshed successfully", loginResp))
+}
+
+// Logout ends the session associated with a refresh token
+// @Summary      User logout
+// @Description  Log out by revoking the given refresh token's family. If a Bearer access token is sent in the Authorization header it is revoked as well.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        logoutRequest body domain.RefreshTokenRequest true "Refresh token"
+// @Success      200 {object} domain.Response{data=string} "Logout successful"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid JSON format or missing required fields"
+// @Failure      401 {object} domain.Problem "Unauthorized - Invalid refresh token"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var logoutReq domain.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&logoutReq); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	accessToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if err := h.authSvc.Logout(c.Request.Context(), logoutReq.RefreshToken, accessToken); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Logout successful", ""))
+}
+
+// ForgotPassword emails a password reset link
+// @Summary      Request a password reset
+// @Description  Email a single-use password reset link to the account. The response is the same whether or not the email is registered.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        forgotRequest body domain.ForgotPasswordRequest true "Account email"
+// @Success      200 {object} domain.Response{data=string} "Reset link sent if the account exists"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid JSON format or missing required fields"
+// @Failure      500 {object} domain.Problem "Internal server error - Failed to send email"
+// @Router       /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var forgotReq domain.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&forgotReq); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	if err := h.accountSvc.ForgotPassword(c.Request.Context(), forgotReq.Email); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("If the account exists, a reset link has been sent", ""))
+}
+
+// ResetPassword sets a new password with a reset token
+// @Summary      Reset password
+// @Description  Set a new password using the token from the reset email. All existing sessions of the user are signed out and their API keys revoked.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        resetRequest body domain.ResetPasswordRequest true "Reset token and new password"
+// @Success      200 {object} domain.Response{data=string} "Password reset successful"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid, expired or used token"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var resetReq domain.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&resetReq); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	if err := h.accountSvc.ResetPassword(c.Request.Context(), resetReq); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Password reset successful", ""))
+}
+
+// VerifyEmail confirms the user's email address
+// @Summary      Verify email address
+// @Description  Confirm an email address using the token from the verification email
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        verifyRequest body domain.VerifyEmailRequest true "Verification token"
+// @Success      200 {object} domain.Response{data=string} "Email verified"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid, expired or used token"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/verify [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var verifyReq domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&verifyReq); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	if err := h.accountSvc.VerifyEmail(c.Request.Context(), verifyReq.Token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Email verified", ""))
+}
+
+// ResendVerification emails a new verification link to the current user
+// @Summary      Resend verification email
+// @Description  Email a new verification link to the signed-in user
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.Response{data=string} "Verification email sent"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      409 {object} domain.Problem "Conflict - Email already verified"
+// @Failure      500 {object} domain.Problem "Internal server error - Failed to send email"
+// @Router       /auth/verify/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	if err := h.accountSvc.SendVerificationEmail(c.Request.Context(), c.GetString("userID")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Verification email sent", ""))
+}
+
+// setRetryAfter tells throttled clients how long to wait before trying again
+func setRetryAfter(c *gin.Context, err error) {
+	var throttled *domain.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	}
+}
@@ -25,44 +25,57 @@ func NewBarHandler(barSvc port.BarService) *BarHandler {
 // @Security     BearerAuth
 // @Param        bar body domain.Bar true "Bar creation data"
 // @Success      201 {object} domain.Response{data=domain.Bar} "Successfully created bar"
-// @Failure      400 {object} domain.Response "Bad request - Invalid input data"
-// @Failure      401 {object} domain.Response "Unauthorized - Missing or invalid token"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid input data"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /bar [post]
 func (bh *BarHandler) Create(c *gin.Context) {
 	var bar domain.Bar
 	if err := c.ShouldBindJSON(&bar); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse(err.Error()))
+		_ = c.Error(bindError(err))
 		return
 	}
 
 	createdBar, err := bh.barSvc.Create(c.Request.Context(), &bar)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, domain.SuccessResponseWithMessage("Bar created successfully", createdBar))
 }
 
-// GetAll retrieves all bar items
-// @Summary      Get all bars
-// @Description  Retrieve all bar items from the database. Requires authentication.
+// GetAll retrieves a page of bar items
+// @Summary      List bars
+// @Description  Retrieve a page of bar items, optionally filtered and sorted. Filter with field=value or field__op=value, where op is one of eq, ne, gt, gte, lt, lte, in (comma-separated values) or contains. Requires authentication.
 // @Tags         bar
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200 {object} domain.Response{data=[]domain.Bar} "Successfully retrieved all bars"
-// @Failure      401 {object} domain.Response "Unauthorized - Missing or invalid token"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Param        limit   query  int     false  "Maximum number of items (1-100, default 20)"
+// @Param        offset  query  int     false  "Number of items to skip"
+// @Param        cursor  query  string  false  "next_cursor of the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by; prefix with - for descending"
+// @Success      200 {object} domain.Response{data=domain.Page[domain.Bar]} "Successfully retrieved bars"
+// @Failure      400 {object} domain.Problem "Invalid filter, sort or page"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /bar [get]
 func (bh *BarHandler) GetAll(c *gin.Context) {
-	bars, err := bh.barSvc.GetAll(c.Request.Context())
+	query, err := parseListQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Bars retrieved successfully", bars))
+	page, err := bh.barSvc.List(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Bars retrieved successfully", page))
 }
 
 // GetByID retrieves a bar by ID
@@ -73,16 +86,17 @@ func (bh *BarHandler) GetAll(c *gin.Context) {
 // @Security     BearerAuth
 // @Param        id path string true "Bar ID" example("507f1f77bcf86cd799439011")
 // @Success      200 {object} domain.Response{data=domain.Bar} "Successfully retrieved bar"
-// @Failure      400 {object} domain.Response "Bad request - Invalid ID format"
-// @Failure      401 {object} domain.Response "Unauthorized - Missing or invalid token"
-// @Failure      404 {object} domain.Response "Bar not found"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid ID format"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission"
+// @Failure      404 {object} domain.Problem "Bar not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /bar/{id} [get]
 func (bh *BarHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 	bar, err := bh.barSvc.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -91,7 +105,7 @@ func (bh *BarHandler) GetByID(c *gin.Context) {
 
 // UpdateByID updates a bar by ID
 // @Summary      Update bar by ID
-// @Description  Update a specific bar item by its unique identifier. Requires authentication.
+// @Description  Update a specific bar item by its unique identifier. Only name, description and status can be updated. Requires authentication.
 // @Tags         bar
 // @Accept       json
 // @Produce      json
@@ -99,26 +113,23 @@ func (bh *BarHandler) GetByID(c *gin.Context) {
 // @Param        id path string true "Bar ID" example("507f1f77bcf86cd799439011")
 // @Param        update body map[string]interface{} true "Update data" example({"name": "Updated Bar Name", "description": "Updated description", "status": "inactive"})
 // @Success      200 {object} domain.Response{data=domain.Bar} "Successfully updated bar"
-// @Failure      400 {object} domain.Response "Bad request - Invalid input data or ID format"
-// @Failure      401 {object} domain.Response "Unauthorized - Missing or invalid token"
-// @Failure      404 {object} domain.Response "Bar not found"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid input data, a field that cannot be updated or ID format"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission"
+// @Failure      404 {object} domain.Problem "Bar not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /bar/{id} [put]
 func (bh *BarHandler) UpdateByID(c *gin.Context) {
 	id := c.Param("id")
 	var update map[string]any
 	if err := c.ShouldBindJSON(&update); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse(err.Error()))
+		_ = c.Error(bindError(err))
 		return
 	}
 
 	updatedBar, err := bh.barSvc.UpdateById(c.Request.Context(), id, update)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "bar not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -133,19 +144,16 @@ func (bh *BarHandler) UpdateByID(c *gin.Context) {
 // @Security     BearerAuth
 // @Param        id path string true "Bar ID" example("507f1f77bcf86cd799439011")
 // @Success      200 {object} domain.Response{data=string} "Successfully deleted bar"
-// @Failure      400 {object} domain.Response "Bad request - Invalid ID format"
-// @Failure      401 {object} domain.Response "Unauthorized - Missing or invalid token"
-// @Failure      404 {object} domain.Response "Bar not found"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid ID format"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission"
+// @Failure      404 {object} domain.Problem "Bar not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /bar/{id} [delete]
 func (bh *BarHandler) DeleteByID(c *gin.Context) {
 	id := c.Param("id")
 	if err := bh.barSvc.DeleteById(c.Request.Context(), id); err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "bar not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
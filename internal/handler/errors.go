@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-gin-boilerplate/internal/domain"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report invalid fields by their JSON names rather than the Go ones
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// bindError converts a request that failed to bind into a validation error,
+// listing the invalid fields when the validator reported them
+func bindError(err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]domain.FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, domain.FieldError{
+				Field:   fieldPath(fieldErr),
+				Message: validationMessage(fieldErr),
+			})
+		}
+		return domain.NewValidationError("request validation failed", fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return domain.NewValidationError("request validation failed", domain.FieldError{
+			Field:   typeErr.Field,
+			Message: "must be a " + typeErr.Type.String(),
+		})
+	}
+
+	return domain.WrapError(domain.ErrBadRequest, err.Error(), err)
+}
+
+// fieldPath returns the field's path below the request body, e.g. "scopes[0]"
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fieldErr.Field()
+}
+
+// validationMessage describes a failed validation rule
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "min":
+		return fmt.Sprintf("must be at least %s", lengthOrValue(fieldErr))
+	case "max":
+		return fmt.Sprintf("must be at most %s", lengthOrValue(fieldErr))
+	case "len":
+		return fmt.Sprintf("must be exactly %s", lengthOrValue(fieldErr))
+	case "oneof":
+		return "must be one of: " + fieldErr.Param()
+	default:
+		return "is invalid"
+	}
+}
+
+// lengthOrValue formats the parameter of a size rule, which limits the length
+// of strings and collections but the value of numbers
+func lengthOrValue(fieldErr validator.FieldError) string {
+	switch fieldErr.Kind() {
+	case reflect.String:
+		return fieldErr.Param() + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fieldErr.Param() + " items"
+	default:
+		return fieldErr.Param()
+	}
+}
@@ -22,43 +22,57 @@ func NewFooHandler(fooSvc port.FooService) *FooHandler {
 // @Tags         foo
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        foo body domain.Foo true "Foo creation data"
 // @Success      201 {object} domain.Response{data=domain.Foo} "Successfully created foo"
-// @Failure      400 {object} domain.Response "Bad request - Invalid input data"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid input data"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /foo [post]
 func (fh *FooHandler) Create(c *gin.Context) {
 	foo := &domain.Foo{}
 	if err := c.ShouldBindJSON(foo); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse(err.Error()))
+		_ = c.Error(bindError(err))
 		return
 	}
 
 	createdFoo, err := fh.fooSvc.Create(c.Request.Context(), foo)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, domain.SuccessResponseWithMessage("Foo created successfully", createdFoo))
 }
 
-// GetAll retrieves all foo items
-// @Summary      Get all foos
-// @Description  Retrieve all foo items from the database
+// GetAll retrieves a page of foo items
+// @Summary      List foos
+// @Description  Retrieve a page of foo items, optionally filtered and sorted. Filter with field=value or field__op=value, where op is one of eq, ne, gt, gte, lt, lte, in (comma-separated values) or contains.
 // @Tags         foo
 // @Produce      json
-// @Success      200 {object} domain.Response{data=[]domain.Foo} "Successfully retrieved all foos"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Param        limit   query  int     false  "Maximum number of items (1-100, default 20)"
+// @Param        offset  query  int     false  "Number of items to skip"
+// @Param        cursor  query  string  false  "next_cursor of the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by; prefix with - for descending"
+// @Success      200 {object} domain.Response{data=domain.Page[domain.Foo]} "Successfully retrieved foos"
+// @Failure      400 {object} domain.Problem "Invalid filter, sort or page"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /foo [get]
 func (fh *FooHandler) GetAll(c *gin.Context) {
-	foos, err := fh.fooSvc.GetAll(c.Request.Context())
+	query, err := parseListQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Foos retrieved successfully", foos))
+	page, err := fh.fooSvc.List(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Foos retrieved successfully", page))
 }
 
 // GetByID retrieves a foo by ID
@@ -68,15 +82,15 @@ func (fh *FooHandler) GetAll(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "Foo ID" example("507f1f77bcf86cd799439011")
 // @Success      200 {object} domain.Response{data=domain.Foo} "Successfully retrieved foo"
-// @Failure      400 {object} domain.Response "Bad request - Invalid ID format"
-// @Failure      404 {object} domain.Response "Foo not found"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid ID format"
+// @Failure      404 {object} domain.Problem "Foo not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /foo/{id} [get]
 func (fh *FooHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 	foo, err := fh.fooSvc.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -89,28 +103,27 @@ func (fh *FooHandler) GetByID(c *gin.Context) {
 // @Tags         foo
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id path string true "Foo ID" example("507f1f77bcf86cd799439011")
 // @Param        update body map[string]interface{} true "Update data" example({"name": "Updated Foo Name"})
 // @Success      200 {object} domain.Response{data=domain.Foo} "Successfully updated foo"
-// @Failure      400 {object} domain.Response "Bad request - Invalid input data or ID format"
-// @Failure      404 {object} domain.Response "Foo not found"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid input data or ID format"
+// @Failure      404 {object} domain.Problem "Foo not found"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /foo/{id} [put]
 func (fh *FooHandler) UpdateByID(c *gin.Context) {
 	id := c.Param("id")
 	var update map[string]any
 	if err := c.ShouldBindJSON(&update); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse(err.Error()))
+		_ = c.Error(bindError(err))
 		return
 	}
 
 	updatedFoo, err := fh.fooSvc.UpdateById(c.Request.Context(), id, update)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "foo not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -122,20 +135,19 @@ func (fh *FooHandler) UpdateByID(c *gin.Context) {
 // @Description  Delete a specific foo item by its unique identifier
 // @Tags         foo
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id path string true "Foo ID" example("507f1f77bcf86cd799439011")
 // @Success      200 {object} domain.Response{data=string} "Successfully deleted foo"
-// @Failure      400 {object} domain.Response "Bad request - Invalid ID format"
-// @Failure      404 {object} domain.Response "Foo not found"
-// @Failure      500 {object} domain.Response "Internal server error"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid ID format"
+// @Failure      404 {object} domain.Problem "Foo not found"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Missing permission"
+// @Failure      500 {object} domain.Problem "Internal server error"
 // @Router       /foo/{id} [delete]
 func (fh *FooHandler) DeleteByID(c *gin.Context) {
 	id := c.Param("id")
 	if err := fh.fooSvc.DeleteById(c.Request.Context(), id); err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "foo not found" {
-			statusCode = http.StatusNotFound
-		}
-		c.JSON(statusCode, domain.ErrorResponse(err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
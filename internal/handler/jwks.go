@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"go-gin-boilerplate/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	jwt *utils.JWT
+}
+
+func NewJWKSHandler(jwt *utils.JWT) *JWKSHandler {
+	return &JWKSHandler{jwt: jwt}
+}
+
+// GetJWKS publishes the public keys used to verify issued tokens
+// @Summary      JSON Web Key Set
+// @Description  Public keys for verifying tokens issued by this service (RFC 7517). Empty when tokens are signed with a shared HMAC secret.
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} utils.JSONWebKeySet "Public verification keys"
+// @Router       /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwt.JWKS())
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseListQuery reads the page, sort order and filters of a list request:
+//
+//	?limit=20&offset=40 or ?limit=20&cursor=<next_cursor>
+//	?sort=status,-name  (a leading "-" sorts descending)
+//	?status=active&name__contains=x&owner_id__in=a,b
+func parseListQuery(c *gin.Context) (domain.ListQuery, error) {
+	var query domain.ListQuery
+	var invalid []domain.FieldError
+
+	params := c.Request.URL.Query()
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, param := range names {
+		values := params[param]
+		value := values[len(values)-1]
+		switch param {
+		case "limit", "offset":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				invalid = append(invalid, domain.FieldError{Field: param, Message: "must be an integer"})
+				continue
+			}
+			if param == "offset" {
+				query.Offset = n
+			} else if n < 1 || n > domain.MaxListLimit {
+				invalid = append(invalid, domain.FieldError{Field: param, Message: "must be between 1 and " + strconv.Itoa(domain.MaxListLimit)})
+			} else {
+				query.Limit = n
+			}
+		case "cursor":
+			query.Cursor = value
+		case "sort":
+			for _, field := range strings.Split(value, ",") {
+				if field = strings.TrimSpace(field); field == "" {
+					continue
+				}
+				desc := strings.HasPrefix(field, "-")
+				query.Sort = append(query.Sort, domain.SortField{Field: strings.TrimPrefix(field, "-"), Desc: desc})
+			}
+		default:
+			field, operator, _ := strings.Cut(param, "__")
+			filter := domain.FieldFilter{Field: field, Operator: domain.FilterOperator(operator)}
+			if operator == "" {
+				filter.Operator = domain.FilterEq
+			}
+			if !slices.Contains(domain.FilterOperators, filter.Operator) {
+				invalid = append(invalid, domain.FieldError{Field: param, Message: "has an unknown operator"})
+				continue
+			}
+			for _, value := range values {
+				filter.Value = value
+				if filter.Operator == domain.FilterIn {
+					filter.Value = strings.Split(value, ",")
+				}
+				query.Filters = append(query.Filters, filter)
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return query, domain.NewValidationError("invalid list query", invalid...)
+	}
+	return query, nil
+}
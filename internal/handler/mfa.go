@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaSvc port.MFAService
+}
+
+func NewMFAHandler(mfaSvc port.MFAService) *MFAHandler {
+	return &MFAHandler{mfaSvc: mfaSvc}
+}
+
+// Enroll starts TOTP enrollment for the current user
+// @Summary      Enroll in MFA
+// @Description  Generate a TOTP secret, otpauth URI and recovery codes for the current user. MFA is enforced only after activation.
+// @Tags         mfa
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.Response{data=domain.MFAEnrollResponse} "Enrollment started"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Delegated credentials cannot manage MFA"
+// @Failure      409 {object} domain.Problem "Conflict - MFA is already enabled"
+// @Router       /auth/mfa/enroll [post]
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	enrollment, err := h.mfaSvc.Enroll(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("MFA enrollment started", enrollment))
+}
+
+// Activate enables MFA after confirming a code
+// @Summary      Activate MFA
+// @Description  Confirm enrollment with a code from the authenticator app; from then on login requires a second factor
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        codeRequest body domain.MFACodeRequest true "TOTP code"
+// @Success      200 {object} domain.Response{data=string} "MFA enabled"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid code or not enrolled"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Delegated credentials cannot manage MFA"
+// @Failure      409 {object} domain.Problem "Conflict - MFA is already enabled"
+// @Router       /auth/mfa/activate [post]
+func (h *MFAHandler) Activate(c *gin.Context) {
+	var req domain.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	if err := h.mfaSvc.Activate(c.Request.Context(), req.Code); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("MFA enabled", ""))
+}
+
+// Disable turns MFA off
+// @Summary      Disable MFA
+// @Description  Disable MFA for the current user after checking a TOTP or recovery code
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        codeRequest body domain.MFACodeRequest true "TOTP or recovery code"
+// @Success      200 {object} domain.Response{data=string} "MFA disabled"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid code or MFA not enabled"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Delegated credentials cannot manage MFA"
+// @Router       /auth/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	var req domain.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	if err := h.mfaSvc.Disable(c.Request.Context(), req.Code); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("MFA disabled", ""))
+}
+
+// Verify completes a login that requires a second factor
+// @Summary      Verify MFA login
+// @Description  Exchange the MFA challenge token returned by login and a TOTP or recovery code for JWT access and refresh tokens. Wrong codes count as failed logins, and a challenge token is revoked after three of them.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        verifyRequest body domain.MFAVerifyRequest true "Challenge token and code"
+// @Success      200 {object} domain.Response{data=domain.LoginResponse} "Login successful - Returns access and refresh tokens"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid JSON format or missing required fields"
+// @Failure      401 {object} domain.Problem "Unauthorized - Invalid challenge token or code"
+// @Failure      429 {object} domain.Problem "Too many requests - Too many failed attempts, retry after the Retry-After header"
+// @Header       429 {integer} Retry-After "Seconds to wait before the next attempt"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/mfa/verify [post]
+func (h *MFAHandler) Verify(c *gin.Context) {
+	var req domain.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	loginResp, err := h.mfaSvc.Verify(c.Request.Context(), req)
+	if err != nil {
+		setRetryAfter(c, err)
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Login successful", loginResp))
+}
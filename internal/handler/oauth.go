@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"errors"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthHandler struct {
+	oauthSvc port.OAuthService
+}
+
+func NewOAuthHandler(oauthSvc port.OAuthService) *OAuthHandler {
+	return &OAuthHandler{oauthSvc: oauthSvc}
+}
+
+// RegisterClient registers a new OAuth2 client
+// @Summary      Register an OAuth2 client
+// @Description  Register a client for the client_credentials and/or authorization_code grants. The client secret is returned only once. Requires the clients:manage permission.
+// @Tags         oauth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        client body domain.RegisterOAuthClientRequest true "Client registration data"
+// @Success      201 {object} domain.Response{data=domain.RegisterOAuthClientResponse} "Successfully registered client"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid input data"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Insufficient permissions"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /oauth/clients [post]
+func (h *OAuthHandler) RegisterClient(c *gin.Context) {
+	var req domain.RegisterOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	registered, err := h.oauthSvc.RegisterClient(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.SuccessResponseWithMessage("OAuth client registered successfully", registered))
+}
+
+// ListClients lists the registered OAuth2 clients
+// @Summary      List OAuth2 clients
+// @Description  Retrieve all registered clients without their secrets. Requires the clients:manage permission.
+// @Tags         oauth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.Response{data=[]domain.OAuthClient} "Successfully retrieved clients"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Insufficient permissions"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /oauth/clients [get]
+func (h *OAuthHandler) ListClients(c *gin.Context) {
+	clients, err := h.oauthSvc.ListClients(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("OAuth clients retrieved successfully", clients))
+}
+
+// DeleteClient removes an OAuth2 client
+// @Summary      Delete an OAuth2 client
+// @Description  Delete a registered client so it can no longer obtain tokens. Requires the clients:manage permission.
+// @Tags         oauth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Client ID" example("9f86d081884c7d659a2feaa0c55ad015")
+// @Success      200 {object} domain.Response{data=string} "Successfully deleted client"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Insufficient permissions"
+// @Failure      404 {object} domain.Problem "OAuth client not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /oauth/clients/{id} [delete]
+func (h *OAuthHandler) DeleteClient(c *gin.Context) {
+	if err := h.oauthSvc.DeleteClient(c.Request.Context(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("OAuth client deleted successfully", ""))
+}
+
+// Authorize issues an authorization code to the signed-in user
+// @Summary      OAuth2 authorization endpoint
+// @Description  Approve a client's authorization request on behalf of the signed-in user and redirect to the client's redirect_uri with an authorization code. PKCE (S256) is required for public clients.
+// @Tags         oauth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        response_type query string true "Must be code"
+// @Param        client_id query string true "Client ID"
+// @Param        redirect_uri query string true "Registered redirect URI"
+// @Param        scope query string false "Space-separated scopes; defaults to every scope the client and user may use"
+// @Param        state query string false "Opaque value returned to the client"
+// @Param        code_challenge query string false "PKCE code challenge"
+// @Param        code_challenge_method query string false "PKCE method, must be S256"
+// @Success      302 "Redirect to the client with code and state"
+// @Failure      400 {object} domain.Problem "Bad request - Invalid authorization request"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Delegated credentials cannot authorize clients"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /oauth/authorize [get]
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	var req domain.AuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	redirectURL, err := h.oauthSvc.Authorize(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// Token issues access tokens to OAuth2 clients
+// @Summary      OAuth2 token endpoint
+// @Description  Issue an access token for the client_credentials or authorization_code grant. Clients authenticate with HTTP Basic or client_id/client_secret form fields. Responses follow RFC 6749 rather than the usual envelope.
+// @Tags         oauth
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        grant_type formData string true "client_credentials or authorization_code"
+// @Param        client_id formData string false "Client ID when not using HTTP Basic"
+// @Param        client_secret formData string false "Client secret when not using HTTP Basic"
+// @Param        scope formData string false "Space-separated scopes"
+// @Param        code formData string false "Authorization code"
+// @Param        redirect_uri formData string false "Redirect URI used to obtain the code"
+// @Param        code_verifier formData string false "PKCE code verifier"
+// @Success      200 {object} domain.TokenResponse "Access token issued"
+// @Failure      400 {object} domain.OAuthErrorResponse "Invalid request, grant or scope"
+// @Failure      401 {object} domain.OAuthErrorResponse "Client authentication failed"
+// @Router       /oauth/token [post]
+func (h *OAuthHandler) Token(c *gin.Context) {
+	var req domain.TokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.oauthError(c, domain.ErrOAuthInvalidRequest)
+		return
+	}
+	if clientID, clientSecret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID, req.ClientSecret = clientID, clientSecret
+	}
+
+	token, err := h.oauthSvc.Token(c.Request.Context(), req)
+	if err != nil {
+		h.oauthError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
+
+// Introspect describes a token to a confidential client
+// @Summary      OAuth2 token introspection
+// @Description  Report whether an access token is active and describe it (RFC 7662). The caller authenticates as a confidential client with HTTP Basic or client_id/client_secret form fields.
+// @Tags         oauth
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        token formData string true "Token to introspect"
+// @Param        client_id formData string false "Client ID when not using HTTP Basic"
+// @Param        client_secret formData string false "Client secret when not using HTTP Basic"
+// @Success      200 {object} domain.IntrospectionResponse "Token description"
+// @Failure      400 {object} domain.OAuthErrorResponse "Missing token"
+// @Failure      401 {object} domain.OAuthErrorResponse "Client authentication failed"
+// @Router       /oauth/introspect [post]
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	token := c.PostForm("token")
+	if token == "" {
+		h.oauthError(c, domain.ErrOAuthInvalidRequest)
+		return
+	}
+	clientID, clientSecret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID, clientSecret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+
+	introspection, err := h.oauthSvc.Introspect(c.Request.Context(), clientID, clientSecret, token)
+	if err != nil {
+		h.oauthError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, introspection)
+}
+
+// oauthErrorCodes lists the sentinels reported as RFC 6749 error codes
+var oauthErrorCodes = []error{
+	domain.ErrOAuthInvalidRequest,
+	domain.ErrOAuthInvalidClient,
+	domain.ErrOAuthInvalidGrant,
+	domain.ErrOAuthUnauthorizedClient,
+	domain.ErrOAuthUnsupportedGrantType,
+	domain.ErrOAuthInvalidScope,
+}
+
+// oauthError writes an RFC 6749 error response; unknown errors become server_error
+func (h *OAuthHandler) oauthError(c *gin.Context, err error) {
+	for _, code := range oauthErrorCodes {
+		if !errors.Is(err, code) {
+			continue
+		}
+		statusCode := http.StatusBadRequest
+		if code == domain.ErrOAuthInvalidClient {
+			statusCode = http.StatusUnauthorized
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		c.JSON(statusCode, domain.OAuthErrorResponse{
+			Error:            code.Error(),
+			ErrorDescription: strings.TrimPrefix(strings.TrimPrefix(err.Error(), code.Error()), ": "),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, domain.OAuthErrorResponse{Error: "server_error"})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCHandler struct {
+	oidcSvc port.OIDCService
+}
+
+func NewOIDCHandler(oidcSvc port.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidcSvc: oidcSvc}
+}
+
+// Login redirects the user agent to an external identity provider
+// @Summary      Start external login
+// @Description  Start an OpenID Connect authorization code flow with PKCE by redirecting to the configured provider
+// @Tags         auth
+// @Produce      json
+// @Param        provider path string true "Provider name from the oidc.providers config" example("google")
+// @Success      302 "Redirect to the provider's authorization endpoint"
+// @Failure      404 {object} domain.Problem "Provider not configured"
+// @Failure      502 {object} domain.Problem "Provider discovery failed"
+// @Router       /auth/oidc/{provider} [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	url, err := h.oidcSvc.AuthorizationURL(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+// Callback completes an external login
+// @Summary      Complete external login
+// @Description  Exchange the authorization code returned by the provider, link or provision the local user and return JWT access and refresh tokens. Users with MFA enabled get mfa_required and an mfa_token to complete the login at /auth/mfa/verify.
+// @Tags         auth
+// @Produce      json
+// @Param        provider path string true "Provider name from the oidc.providers config" example("google")
+// @Param        code query string true "Authorization code"
+// @Param        state query string true "State issued when the login started"
+// @Success      200 {object} domain.Response{data=domain.LoginResponse} "Login successful - Returns access and refresh tokens"
+// @Failure      400 {object} domain.Problem "Bad request - Missing code or state"
+// @Failure      401 {object} domain.Problem "Unauthorized - Login rejected by the provider or invalid response"
+// @Failure      404 {object} domain.Problem "Provider not configured"
+// @Failure      409 {object} domain.Problem "Conflict - A local account with this email has not verified it"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		_ = c.Error(domain.NewUnauthorizedError("login rejected by provider: " + providerErr))
+		return
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		_ = c.Error(domain.NewValidationError("code and state are required"))
+		return
+	}
+
+	loginResp, err := h.oidcSvc.Callback(c.Request.Context(), c.Param("provider"), code, state)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Login successful", loginResp))
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	sessionSvc port.SessionService
+}
+
+func NewSessionHandler(sessionSvc port.SessionService) *SessionHandler {
+	return &SessionHandler{sessionSvc: sessionSvc}
+}
+
+// List lists the current user's active sessions
+// @Summary      List sessions
+// @Description  Retrieve the current user's active logins with device, IP address and last use. The session of the calling token is marked current.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.Response{data=[]domain.Session} "Successfully retrieved sessions"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Delegated credentials cannot manage sessions"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	sessions, err := h.sessionSvc.List(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Sessions retrieved successfully", sessions))
+}
+
+// Revoke ends one of the current user's sessions
+// @Summary      Revoke a session
+// @Description  End one of the current user's sessions. Its refresh token can no longer be used and its access tokens are rejected.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Session ID" example("9f86d081884c7d659a2feaa0c55ad015")
+// @Success      200 {object} domain.Response{data=string} "Successfully revoked session"
+// @Failure      401 {object} domain.Problem "Unauthorized - Missing or invalid token"
+// @Failure      403 {object} domain.Problem "Forbidden - Delegated credentials cannot manage sessions"
+// @Failure      404 {object} domain.Problem "Session not found"
+// @Failure      500 {object} domain.Problem "Internal server error"
+// @Router       /auth/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	if err := h.sessionSvc.Revoke(c.Request.Context(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponseWithMessage("Session revoked successfully", ""))
+}
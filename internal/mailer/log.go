@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type logMailer struct {
+	from string
+	dir  string
+}
+
+// NewLogMailer returns a Mailer for development and tests that writes each
+// message as an .eml file to dir, or to the application log if dir is empty
+func NewLogMailer(from, dir string) port.Mailer {
+	return &logMailer{from: from, dir: dir}
+}
+
+func (m *logMailer) Send(_ context.Context, message domain.EmailMessage) error {
+	data := formatMessage(m.from, message)
+	if m.dir == "" {
+		log.Printf("Email to %s:\n%s", message.To, data)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), filepath.Base(message.To))
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o600)
+}
@@ -0,0 +1,14 @@
+package mailer
+
+import (
+	"go-gin-boilerplate/config"
+	"go-gin-boilerplate/internal/port"
+)
+
+// NewMailer returns the Mailer selected by the configured driver
+func NewMailer(cfg *config.MailConfig) port.Mailer {
+	if cfg.Driver == "smtp" {
+		return NewSMTPMailer(cfg)
+	}
+	return NewLogMailer(cfg.From, cfg.Dir)
+}
@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"go-gin-boilerplate/config"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer returns a Mailer delivering through an SMTP server. STARTTLS
+// is used when the server offers it; credentials are optional.
+func NewSMTPMailer(cfg *config.MailConfig) port.Mailer {
+	m := &smtpMailer{
+		addr: net.JoinHostPort(cfg.SMTP.Host, cfg.SMTP.Port),
+		from: cfg.From,
+	}
+	if cfg.SMTP.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.Host)
+	}
+	return m
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message domain.EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{message.To}, formatMessage(m.from, message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// formatMessage renders a plain-text RFC 5322 message. Header values come
+// from our own templates, but CR/LF are stripped to rule out header injection.
+func formatMessage(from string, message domain.EmailMessage) []byte {
+	header := strings.NewReplacer("\r", "", "\n", "")
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(message.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", header.Replace(message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -1,39 +1,127 @@
 package middleware
 
 import (
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/revocation"
 	"go-gin-boilerplate/internal/utils"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwt *utils.JWT) gin.HandlerFunc {
+// AuthMiddleware authenticates the request with either an
+// `Authorization: Bearer <jwt>` header or, when apiKeySvc is set, an
+// `X-API-Key` header, and stores the caller's identity in the context
+func AuthMiddleware(jwt *utils.JWT, revocationStore port.TokenRevocationStore, apiKeySvc port.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" && apiKeySvc != nil {
+			identity, err := apiKeySvc.Authenticate(c.Request.Context(), apiKey)
+			if err != nil {
+				abortWithProblem(c, domain.NewProblem(http.StatusUnauthorized, domain.CodeUnauthorized, "missing or invalid credentials"))
+				return
+			}
+			setIdentity(c, identity)
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort()
+			abortWithProblem(c, domain.NewProblem(http.StatusUnauthorized, domain.CodeUnauthorized, "missing or invalid credentials"))
 			return
 		}
 
 		// Check if the token has the Bearer prefix
 		if len(authHeader) < 7 || authHeader[:7] != "Bearer " {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format, expected 'Bearer <token>'"})
-			c.Abort()
+			abortWithProblem(c, domain.NewProblem(http.StatusUnauthorized, domain.CodeUnauthorized, "invalid authorization format, expected 'Bearer <token>'"))
 			return
 		}
 
 		// Extract the token without the Bearer prefix
 		token := authHeader[7:]
 
-		userID, err := jwt.ValidateToken(token)
+		claims, err := jwt.ValidateAccessToken(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort()
+			abortWithProblem(c, domain.NewProblem(http.StatusUnauthorized, domain.CodeUnauthorized, "missing or invalid credentials"))
 			return
 		}
 
-		c.Set("userID", userID)
+		revoked, err := revocation.IsTokenRevoked(c.Request.Context(), revocationStore, claims)
+		if err != nil {
+			abortWithProblem(c, domain.NewProblem(http.StatusInternalServerError, domain.CodeInternal, "failed to check token revocation"))
+			return
+		}
+		if revoked {
+			abortWithProblem(c, domain.NewProblem(http.StatusUnauthorized, domain.CodeUnauthorized, "token has been revoked"))
+			return
+		}
+
+		identity := &domain.Identity{
+			UserID:    claims.Subject,
+			Roles:     claims.Roles,
+			SessionID: claims.FamilyID,
+		}
+		if claims.ClientID != "" {
+			identity.ClientID = claims.ClientID
+			identity.Scopes = append([]string{}, strings.Fields(claims.Scope)...)
+		}
+		if claims.Actor != nil {
+			identity.ActorID = claims.Actor.Subject
+		}
+		setIdentity(c, identity)
+		if identity.IsImpersonated() {
+			defer auditImpersonation(c, identity)
+		}
 		c.Next()
 	}
 }
+
+// auditImpersonation logs a request made by an administrator acting as
+// another user once it has been handled, including requests that panicked
+func auditImpersonation(c *gin.Context, identity *domain.Identity) {
+	status := c.Writer.Status()
+	recovered := recover()
+	if recovered != nil {
+		// ErrorHandler turns the panic into a 500 once it has been audited
+		status = http.StatusInternalServerError
+	}
+	slog.Info("impersonated request",
+		"actor", identity.ActorID,
+		"subject", identity.UserID,
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"status", status,
+		"ip", c.ClientIP())
+	if recovered != nil {
+		panic(recovered)
+	}
+}
+
+// ClientInfo records the caller's IP address and user agent in the request
+// context so that sessions started by the request can be labelled
+func ClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userAgent := c.Request.UserAgent()
+		c.Request = c.Request.WithContext(domain.WithClientInfo(c.Request.Context(), domain.ClientInfo{
+			IP:        c.ClientIP(),
+			UserAgent: userAgent,
+			Device:    utils.DeviceName(userAgent),
+		}))
+		c.Next()
+	}
+}
+
+// setIdentity exposes the caller both in the gin context and in the request
+// context used by the services. For impersonation tokens userID is the
+// impersonated user and actorID the administrator behind the request.
+func setIdentity(c *gin.Context, identity *domain.Identity) {
+	c.Set("userID", identity.UserID)
+	c.Set("roles", identity.Roles)
+	if identity.IsImpersonated() {
+		c.Set("actorID", identity.ActorID)
+	}
+	c.Request = c.Request.WithContext(domain.WithIdentity(c.Request.Context(), identity))
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"fmt"
+	"go-gin-boilerplate/internal/domain"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler turns the errors handlers record with c.Error into problem
+// responses and recovers from panics. Unless the response was already
+// written, the last recorded error picks the status. Internal errors and
+// panics are logged; with hideDetails set, as in production, their message
+// is not sent to the client.
+func ErrorHandler(hideDetails bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if recovered == http.ErrAbortHandler {
+					// The client connection is gone; let net/http handle it
+					panic(recovered)
+				}
+				slog.Error("panic recovered",
+					"method", c.Request.Method,
+					"path", c.Request.URL.Path,
+					"panic", fmt.Sprint(recovered),
+					"stack", string(debug.Stack()))
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				problem := domain.NewProblem(http.StatusInternalServerError, domain.CodeInternal, fmt.Sprint(recovered))
+				abortWithProblem(c, hideInternalDetail(problem, hideDetails))
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		for _, ginErr := range c.Errors {
+			if domain.ProblemFromError(ginErr.Err).Status >= http.StatusInternalServerError || c.Writer.Written() {
+				slog.Error("request failed",
+					"method", c.Request.Method,
+					"path", c.Request.URL.Path,
+					"error", ginErr.Err.Error())
+			}
+		}
+		if c.Writer.Written() {
+			return
+		}
+		problem := domain.ProblemFromError(c.Errors.Last().Err)
+		abortWithProblem(c, hideInternalDetail(problem, hideDetails))
+	}
+}
+
+// hideInternalDetail replaces the detail of an internal server error with a
+// generic message when details must not reach clients
+func hideInternalDetail(problem domain.Problem, hideDetails bool) domain.Problem {
+	if hideDetails && problem.Status == http.StatusInternalServerError {
+		problem.Detail = "an internal error occurred"
+	}
+	return problem
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"go-gin-boilerplate/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abortWithProblem stops the request with a problem response
+func abortWithProblem(c *gin.Context, problem domain.Problem) {
+	problem.Instance = c.Request.URL.Path
+	c.Header("Content-Type", domain.ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
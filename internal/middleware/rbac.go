@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole allows the request only if the authenticated user has at least
+// one of the given roles. It must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := domain.IdentityFromContext(c.Request.Context())
+		if identity == nil || !domain.HasRole(identity.Roles, roles...) {
+			abortWithProblem(c, domain.NewProblem(http.StatusForbidden, domain.CodeForbidden, "insufficient role"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission allows the request only if the authenticated user's roles
+// (and API key scopes, if any) grant every given permission. It must run
+// after AuthMiddleware.
+func RequirePermission(permissions ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := domain.IdentityFromContext(c.Request.Context())
+		for _, permission := range permissions {
+			if identity == nil || !identity.Can(permission) {
+				abortWithProblem(c, domain.NewProblem(http.StatusForbidden, domain.CodeForbidden, "missing permission: "+string(permission)))
+				return
+			}
+		}
+		c.Next()
+	}
+}
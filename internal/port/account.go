@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+// AccountService handles account recovery and email verification through
+// single-use tokens sent by email
+type AccountService interface {
+	// ForgotPassword mails a reset link if the email belongs to a user; it
+	// does not reveal whether it does
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, req domain.ResetPasswordRequest) error
+	SendVerificationEmail(ctx context.Context, userID string) error
+	VerifyEmail(ctx context.Context, token string) error
+}
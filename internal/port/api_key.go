@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, apiKey *domain.APIKey) (*domain.APIKey, error)
+	GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	GetAllByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error)
+	UpdateById(ctx context.Context, id string, update map[string]any) (*domain.APIKey, error)
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, req domain.CreateAPIKeyRequest) (domain.CreateAPIKeyResponse, error)
+	List(ctx context.Context) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	// Authenticate resolves a plaintext API key to the identity of its owner
+	Authenticate(ctx context.Context, key string) (*domain.Identity, error)
+}
@@ -1,10 +1,29 @@
 package port
 
-import "go-gin-boilerplate/internal/domain"
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
 
 type AuthService interface {
-	Login(req domain.LoginRequest) (domain.LoginResponse, error)
-	// Register(req domain.RegisterRequest) (domain.RegisterResponse, error)
-	// Logout(token string) error
-	// RefreshToken(token string) (domain.LoginResponse, error)
+	Login(ctx context.Context, req domain.LoginRequest) (domain.LoginResponse, error)
+	Register(ctx context.Context, req domain.RegisterRequest) (domain.RegisterResponse, error)
+	Logout(ctx context.Context, refreshToken string, accessToken string) error
+	RefreshToken(ctx context.Context, refreshToken string) (domain.LoginResponse, error)
+	RevokeUserTokens(ctx context.Context, userID string) error
+	// IssueTokens starts a new session for a user authenticated by other means,
+	// or returns an MFA challenge when the user has MFA enabled
+	IssueTokens(ctx context.Context, user *domain.User) (domain.LoginResponse, error)
+	// IssueMFATokens starts a new session once the second factor is verified
+	IssueMFATokens(ctx context.Context, user *domain.User) (domain.LoginResponse, error)
+	// Impersonate issues the calling administrator a short-lived access token
+	// acting as the given user
+	Impersonate(ctx context.Context, userID string) (domain.ImpersonationResponse, error)
+}
+
+// SessionService lets users review and end their own login sessions
+type SessionService interface {
+	List(ctx context.Context) ([]domain.Session, error)
+	// Revoke ends a session, invalidating its refresh and access tokens
+	Revoke(ctx context.Context, id string) error
 }
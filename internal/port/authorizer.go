@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+// Authorizer decides whether the identity in ctx may perform an action on a
+// resource. It returns domain.ErrForbidden when access is denied.
+type Authorizer interface {
+	Authorize(ctx context.Context, action domain.Action, resource any) error
+}
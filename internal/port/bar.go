@@ -6,17 +6,14 @@ import (
 )
 
 type BarRepository interface {
-	Create(ctx context.Context, bar *domain.Bar) (*domain.Bar, error)
-	GetAll(ctx context.Context) ([]*domain.Bar, error)
-	GetByID(ctx context.Context, id string) (*domain.Bar, error)
+	Repository[domain.Bar]
 	GetByName(ctx context.Context, name string) (*domain.Bar, error)
-	UpdateById(ctx context.Context, id string, update map[string]any) (*domain.Bar, error)
-	DeleteById(ctx context.Context, id string) error
 }
 
 type BarService interface {
 	Create(ctx context.Context, bar *domain.Bar) (*domain.Bar, error)
 	GetAll(ctx context.Context) ([]*domain.Bar, error)
+	List(ctx context.Context, query domain.ListQuery) (domain.Page[*domain.Bar], error)
 	GetByID(ctx context.Context, id string) (*domain.Bar, error)
 	GetByName(ctx context.Context, name string) (*domain.Bar, error)
 	UpdateById(ctx context.Context, id string, update map[string]any) (*domain.Bar, error)
@@ -6,17 +6,14 @@ import (
 )
 
 type FooRepository interface {
-	Create(ctx context.Context, foo *domain.Foo) (*domain.Foo, error)
-	GetAll(ctx context.Context) ([]*domain.Foo, error)
-	GetByID(ctx context.Context, id string) (*domain.Foo, error)
+	Repository[domain.Foo]
 	GetByName(ctx context.Context, name string) (*domain.Foo, error)
-	UpdateById(ctx context.Context, id string, update map[string]any) (*domain.Foo, error)
-	DeleteById(ctx context.Context, id string) error
 }
 
 type FooService interface {
 	Create(ctx context.Context, foo *domain.Foo) (*domain.Foo, error)
 	GetAll(ctx context.Context) ([]*domain.Foo, error)
+	List(ctx context.Context, query domain.ListQuery) (domain.Page[*domain.Foo], error)
 	GetByID(ctx context.Context, id string) (*domain.Foo, error)
 	GetByName(ctx context.Context, name string) (*domain.Foo, error)
 	UpdateById(ctx context.Context, id string, update map[string]any) (*domain.Foo, error)
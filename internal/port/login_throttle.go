@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"time"
+)
+
+// LoginAttemptStore counts login attempts per key and records temporary
+// lockouts
+type LoginAttemptStore interface {
+	// RecordAttempt counts an attempt made at the given time in one atomic
+	// step, so concurrent attempts all see distinct counts. It returns the
+	// count including the attempt, the time of the attempt before it and any
+	// lockout. The count is forgotten window after the last attempt.
+	RecordAttempt(ctx context.Context, key string, at time.Time, window time.Duration) (domain.LoginAttempts, error)
+	// Forgive uncounts an attempt that turned out not to be a failed guess
+	Forgive(ctx context.Context, key string) error
+	// Lock refuses the key until the given time and clears its count
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginThrottler protects password logins and MFA verification against brute
+// force by account and by client IP. Every attempt is counted before the
+// credentials are checked, so parallel guesses cannot all pass the limits.
+type LoginThrottler interface {
+	// Attempt counts a login attempt and returns a *domain.LoginThrottledError
+	// while the account or the client IP has to wait before trying again
+	Attempt(ctx context.Context, email, ip string) error
+	// Forgive uncounts an attempt that did not fail, e.g. because of an
+	// outage or because the password was right and the second factor is due
+	Forgive(ctx context.Context, email, ip string) error
+	// RecordMFAFailure counts a wrong second factor against the MFA challenge
+	// it was entered for, reporting whether the challenge has used up its
+	// attempts and must be revoked
+	RecordMFAFailure(ctx context.Context, challengeID string) (bool, error)
+	// RecordSuccess clears the account's attempts once a login issued tokens
+	RecordSuccess(ctx context.Context, email, ip string) error
+}
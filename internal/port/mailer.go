@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+// Mailer delivers transactional email such as password reset links
+type Mailer interface {
+	Send(ctx context.Context, message domain.EmailMessage) error
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+type MFAService interface {
+	// Enroll generates a new TOTP secret and recovery codes for the caller;
+	// MFA is enforced only after Activate confirms a code
+	Enroll(ctx context.Context) (domain.MFAEnrollResponse, error)
+	Activate(ctx context.Context, code string) error
+	Disable(ctx context.Context, code string) error
+	// Verify exchanges an MFA challenge token and a code for real tokens
+	Verify(ctx context.Context, req domain.MFAVerifyRequest) (domain.LoginResponse, error)
+}
@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error)
+	GetAll(ctx context.Context) ([]*domain.OAuthClient, error)
+	GetByID(ctx context.Context, id string) (*domain.OAuthClient, error)
+	DeleteById(ctx context.Context, id string) error
+}
+
+// AuthorizationCodeStore keeps issued authorization codes until they are
+// redeemed
+type AuthorizationCodeStore interface {
+	Save(ctx context.Context, code string, authCode *domain.AuthorizationCode) error
+	// Consume returns and deletes the code. It returns
+	// domain.ErrOAuthInvalidGrant if the code is unknown or expired.
+	Consume(ctx context.Context, code string) (*domain.AuthorizationCode, error)
+}
+
+type OAuthService interface {
+	RegisterClient(ctx context.Context, req domain.RegisterOAuthClientRequest) (domain.RegisterOAuthClientResponse, error)
+	ListClients(ctx context.Context) ([]*domain.OAuthClient, error)
+	DeleteClient(ctx context.Context, id string) error
+	// Authorize issues an authorization code to the signed-in user and returns
+	// the client redirect URI carrying it
+	Authorize(ctx context.Context, req domain.AuthorizeRequest) (string, error)
+	Token(ctx context.Context, req domain.TokenRequest) (domain.TokenResponse, error)
+	// Introspect describes a token to an authenticated confidential client
+	Introspect(ctx context.Context, clientID, clientSecret, token string) (domain.IntrospectionResponse, error)
+}
@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+// OIDCStateStore keeps in-flight OIDC logins between redirect and callback
+type OIDCStateStore interface {
+	Save(ctx context.Context, state string, authState *domain.OIDCAuthState) error
+	// Consume returns and deletes the state. It returns
+	// domain.ErrOIDCStateInvalid if the state is unknown or expired.
+	Consume(ctx context.Context, state string) (*domain.OIDCAuthState, error)
+}
+
+type ExternalIdentityRepository interface {
+	Create(ctx context.Context, identity *domain.ExternalIdentity) (*domain.ExternalIdentity, error)
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error)
+}
+
+type OIDCService interface {
+	// AuthorizationURL starts a login and returns the provider URL to redirect to
+	AuthorizationURL(ctx context.Context, provider string) (string, error)
+	// Callback completes a login and issues our own tokens for the linked user
+	Callback(ctx context.Context, provider, code, state string) (domain.LoginResponse, error)
+}
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+// Repository stores entities of type T. Entity repositories embed it and add
+// the queries specific to their entity.
+type Repository[T any] interface {
+	Create(ctx context.Context, entity *T) (*T, error)
+	GetAll(ctx context.Context) ([]*T, error)
+	List(ctx context.Context, query domain.ListQuery) (domain.Page[*T], error)
+	GetByID(ctx context.Context, id string) (*T, error)
+	UpdateById(ctx context.Context, id string, update map[string]any) (*T, error)
+	DeleteById(ctx context.Context, id string) error
+}
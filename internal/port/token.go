@@ -0,0 +1,49 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"time"
+)
+
+// RefreshTokenStore persists issued refresh tokens so they can be rotated
+// exactly once and whole families revoked on reuse.
+type RefreshTokenStore interface {
+	Save(ctx context.Context, record *domain.RefreshTokenRecord) error
+	// MarkUsed atomically marks the token as used and returns its record.
+	// It returns domain.ErrRefreshTokenReused if the token was already used.
+	MarkUsed(ctx context.Context, jti string) (*domain.RefreshTokenRecord, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	IsFamilyRevoked(ctx context.Context, familyID string) (bool, error)
+}
+
+// TokenRevocationStore keeps the list of revoked tokens consulted by
+// AuthMiddleware. Single tokens are revoked by jti and whole sessions by
+// refresh family ID until they expire; all tokens of a subject are revoked by
+// recording a cut-off issue time.
+type TokenRevocationStore interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeOnce revokes jti unless it already is, reporting whether this call
+	// revoked it. Of concurrent calls for the same jti exactly one succeeds,
+	// so it can consume single-use credentials.
+	RevokeOnce(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	RevokeSubject(ctx context.Context, subject string, at time.Time) error
+	// IsSubjectRevoked reports whether a token of the subject issued at
+	// issuedAt was invalidated by RevokeSubject. Token times have second
+	// precision, so a token issued within the same second as the revocation,
+	// even just after it, counts as revoked; clients retry a second later.
+	IsSubjectRevoked(ctx context.Context, subject string, issuedAt time.Time) (bool, error)
+}
+
+// SessionStore keeps a record of every login session, i.e. refresh token
+// family, so users can list and end them. Records expire with the session's
+// latest refresh token.
+type SessionStore interface {
+	// Save creates or updates the session
+	Save(ctx context.Context, session *domain.Session) error
+	// Get returns domain.ErrSessionNotFound for unknown or expired sessions
+	Get(ctx context.Context, id string) (*domain.Session, error)
+	ListByUser(ctx context.Context, userID string) ([]domain.Session, error)
+	Delete(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,16 @@
+package port
+
+import "context"
+
+// UnitOfWork runs fn in a transaction. Repository calls made with the context
+// passed to fn either all take effect or, when fn returns an error, none do.
+//
+// A nested call runs within the enclosing transaction and commits with it,
+// but backends differ when the nested fn fails: on PostgreSQL it is a
+// savepoint, so its own changes are rolled back even if the enclosing fn goes
+// on and succeeds; MongoDB has no savepoints, so its changes are only undone
+// if the enclosing fn fails too. Return nested errors to get the same result
+// on both.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
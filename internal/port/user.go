@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *domain.User) (*domain.User, error)
+	GetByID(ctx context.Context, id string) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	UpdateById(ctx context.Context, id string, update map[string]any) (*domain.User, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+)
+
+type APIKeyRepository struct {
+	*Repository[domain.APIKey, *domain.APIKey]
+}
+
+func NewAPIKeyRepository(baseRepo db.BaseRepository, collection string) port.APIKeyRepository {
+	return &APIKeyRepository{Repository: NewRepository[domain.APIKey](baseRepo, collection)}
+}
+
+func (ar *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	return ar.FindOne(ctx, db.Query{Filter: db.Filter{db.Eq("prefix", prefix)}})
+}
+
+func (ar *APIKeyRepository) GetAllByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	var apiKeys []*domain.APIKey
+	if err := ar.baseRepo.GetAll(ctx, ar.collection, &apiKeys, db.Filter{db.Eq("user_id", userID)}); err != nil {
+		return nil, err
+	}
+	return apiKeys, nil
+}
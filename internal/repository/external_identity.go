@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+)
+
+type ExternalIdentityRepository struct {
+	*Repository[domain.ExternalIdentity, *domain.ExternalIdentity]
+}
+
+func NewExternalIdentityRepository(baseRepo db.BaseRepository, collection string) port.ExternalIdentityRepository {
+	return &ExternalIdentityRepository{Repository: NewRepository[domain.ExternalIdentity](baseRepo, collection)}
+}
+
+// externalIdentityID keys identities by provider and subject so a lookup is a
+// primary key read on every backend
+func externalIdentityID(provider, subject string) string {
+	return provider + ":" + subject
+}
+
+func (er *ExternalIdentityRepository) Create(ctx context.Context, identity *domain.ExternalIdentity) (*domain.ExternalIdentity, error) {
+	return er.createWithID(ctx, identity, externalIdentityID(identity.Provider, identity.Subject))
+}
+
+func (er *ExternalIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error) {
+	return er.GetByID(ctx, externalIdentityID(provider, subject))
+}
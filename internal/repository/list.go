@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+	"strings"
+)
+
+// listCursor is the position after the last item of a page, together with
+// the sort order it is valid for
+type listCursor struct {
+	Sort  string `json:"s"`
+	After []any  `json:"a"`
+}
+
+var errInvalidCursor = domain.NewValidationError("invalid cursor", domain.FieldError{Field: "cursor", Message: "is invalid or does not match the sort order"})
+
+// list returns the page of a collection selected by the query. The sort order
+// always ends with id so that pages are stable, and the next cursor holds the
+// sort values of the last item of the page.
+func list[T any](ctx context.Context, baseRepo db.BaseRepository, collection string, listQuery domain.ListQuery) (domain.Page[*T], error) {
+	query := db.Query{Offset: listQuery.Offset}
+	for _, filter := range listQuery.Filters {
+		query.Filter = append(query.Filter, db.Condition{Field: filter.Field, Op: db.Operator(filter.Operator), Value: filter.Value})
+	}
+	sortedByID := false
+	for _, sort := range listQuery.Sort {
+		query.Sort = append(query.Sort, db.Sort{Field: sort.Field, Desc: sort.Desc})
+		sortedByID = sortedByID || sort.Field == "id"
+	}
+	if !sortedByID {
+		query.Sort = append(query.Sort, db.Sort{Field: "id"})
+	}
+
+	order := sortOrder(query.Sort)
+	if listQuery.Cursor != "" {
+		after, err := decodeCursor(listQuery.Cursor, order)
+		if err != nil {
+			return domain.Page[*T]{}, err
+		}
+		query.After = after
+		query.Offset = 0
+	}
+
+	total, err := baseRepo.Count(ctx, collection, new(T), query.Filter)
+	if err != nil {
+		return domain.Page[*T]{}, err
+	}
+
+	// One extra item tells whether there is a next page
+	if listQuery.Limit > 0 {
+		query.Limit = listQuery.Limit + 1
+	}
+	items := []*T{}
+	if err := baseRepo.Find(ctx, collection, &items, query); err != nil {
+		return domain.Page[*T]{}, err
+	}
+
+	page := domain.Page[*T]{Items: items, Total: total}
+	if listQuery.Limit > 0 && len(items) > listQuery.Limit {
+		page.Items = items[:listQuery.Limit]
+		fields := make([]string, len(query.Sort))
+		for i, sort := range query.Sort {
+			fields[i] = sort.Field
+		}
+		after, err := db.FieldValues(page.Items[len(page.Items)-1], fields)
+		if err != nil {
+			return domain.Page[*T]{}, err
+		}
+		if page.NextCursor, err = encodeCursor(order, after); err != nil {
+			return domain.Page[*T]{}, err
+		}
+	}
+	return page, nil
+}
+
+// sortOrder describes a sort order the way list requests spell it, e.g. "-name,id"
+func sortOrder(sorts []db.Sort) string {
+	fields := make([]string, len(sorts))
+	for i, sort := range sorts {
+		fields[i] = sort.Field
+		if sort.Desc {
+			fields[i] = "-" + sort.Field
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+func encodeCursor(order string, after []any) (string, error) {
+	raw, err := json.Marshal(listCursor{Sort: order, After: after})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(cursor string, order string) ([]any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var decoded listCursor
+	if err := json.Unmarshal(raw, &decoded); err != nil || decoded.Sort != order || len(decoded.After) != strings.Count(order, ",")+1 {
+		return nil, errInvalidCursor
+	}
+	return decoded.After, nil
+}
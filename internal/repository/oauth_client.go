@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/utils"
+)
+
+type OAuthClientRepository struct {
+	*Repository[domain.OAuthClient, *domain.OAuthClient]
+}
+
+func NewOAuthClientRepository(baseRepo db.BaseRepository, collection string) port.OAuthClientRepository {
+	return &OAuthClientRepository{Repository: NewRepository[domain.OAuthClient](baseRepo, collection)}
+}
+
+// Create stores the client under a random ID. The ID doubles as the public
+// client_id, so it is random rather than sequential.
+func (cr *OAuthClientRepository) Create(ctx context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error) {
+	id, err := utils.RandomID(16)
+	if err != nil {
+		return nil, err
+	}
+	return cr.createWithID(ctx, client, id)
+}
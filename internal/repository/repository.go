@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// entity is the pointer type of a domain entity T
+type entity[T any] interface {
+	*T
+	domain.Entity
+}
+
+// Repository implements port.Repository for the entities of type T kept in a
+// collection. A new entity needs a domain struct with a SetID method and, on
+// PostgreSQL, its table added to the migrations in db.InitPgsql:
+//
+//	bazRepo := repository.NewRepository[domain.Baz](baseRepo, "baz")
+//
+// Entity repositories with queries of their own embed it.
+type Repository[T any, PT entity[T]] struct {
+	baseRepo   db.BaseRepository
+	collection string
+}
+
+func NewRepository[T any, PT entity[T]](baseRepo db.BaseRepository, collection string) *Repository[T, PT] {
+	return &Repository[T, PT]{baseRepo: baseRepo, collection: collection}
+}
+
+func (r *Repository[T, PT]) Create(ctx context.Context, entity *T) (*T, error) {
+	return r.createWithID(ctx, entity, primitive.NewObjectID().Hex())
+}
+
+// createWithID stores a new entity under the given ID, for entity
+// repositories whose IDs are not ObjectIDs
+func (r *Repository[T, PT]) createWithID(ctx context.Context, entity *T, id string) (*T, error) {
+	PT(entity).SetID(id)
+	if err := r.baseRepo.Create(ctx, r.collection, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (r *Repository[T, PT]) GetAll(ctx context.Context) ([]*T, error) {
+	var entities []*T
+	if err := r.baseRepo.GetAll(ctx, r.collection, &entities, nil); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *Repository[T, PT]) List(ctx context.Context, query domain.ListQuery) (domain.Page[*T], error) {
+	return list[T](ctx, r.baseRepo, r.collection, query)
+}
+
+func (r *Repository[T, PT]) GetByID(ctx context.Context, id string) (*T, error) {
+	var entity T
+	if err := r.baseRepo.GetById(ctx, r.collection, id, &entity); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// FindOne returns the first entity matching the query
+func (r *Repository[T, PT]) FindOne(ctx context.Context, query db.Query) (*T, error) {
+	var entity T
+	if err := r.baseRepo.FindOne(ctx, r.collection, &entity, query); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *Repository[T, PT]) UpdateById(ctx context.Context, id string, update map[string]any) (*T, error) {
+	if err := r.baseRepo.UpdateById(ctx, r.collection, PT(new(T)), id, update); err != nil {
+		return nil, err
+	}
+
+	// Get the updated entity from database to return the complete object
+	return r.GetByID(ctx, id)
+}
+
+func (r *Repository[T, PT]) DeleteById(ctx context.Context, id string) error {
+	return r.baseRepo.DeleteById(ctx, r.collection, PT(new(T)), id)
+}
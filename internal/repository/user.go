@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+)
+
+type UserRepository struct {
+	*Repository[domain.User, *domain.User]
+}
+
+func NewUserRepository(baseRepo db.BaseRepository, collection string) port.UserRepository {
+	return &UserRepository{Repository: NewRepository[domain.User](baseRepo, collection)}
+}
+
+func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return ur.FindOne(ctx, db.Query{Filter: db.Filter{db.Eq("email", email)}})
+}
@@ -0,0 +1,25 @@
+package revocation
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/utils"
+)
+
+// IsTokenRevoked reports whether a valid token was revoked individually
+// (logout), with its session or in bulk for its subject. Every check of an
+// access token goes through here so that none of them misses a kind of
+// revocation.
+func IsTokenRevoked(ctx context.Context, store port.TokenRevocationStore, claims *utils.Claims) (bool, error) {
+	revoked, err := store.IsRevoked(ctx, claims.ID)
+	if err != nil || revoked {
+		return revoked, err
+	}
+	if claims.FamilyID != "" {
+		revoked, err = store.IsRevoked(ctx, claims.FamilyID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+	return store.IsSubjectRevoked(ctx, claims.Subject, claims.IssuedAt.Time)
+}
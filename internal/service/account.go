@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/revocation"
+	"go-gin-boilerplate/internal/utils"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// errInvalidAccountToken is returned for reset and verification tokens that
+// are malformed, expired, already used or of the wrong kind
+var errInvalidAccountToken = domain.NewValidationError("invalid or expired token")
+
+type AccountService struct {
+	jwt             *utils.JWT
+	userRepo        port.UserRepository
+	apiKeyRepo      port.APIKeyRepository
+	revocationStore port.TokenRevocationStore
+	mailer          port.Mailer
+	linkBaseURL     string
+}
+
+func NewAccountService(jwt *utils.JWT, userRepo port.UserRepository, apiKeyRepo port.APIKeyRepository, revocationStore port.TokenRevocationStore, mailer port.Mailer, linkBaseURL string) port.AccountService {
+	return &AccountService{
+		jwt:             jwt,
+		userRepo:        userRepo,
+		apiKeyRepo:      apiKeyRepo,
+		revocationStore: revocationStore,
+		mailer:          mailer,
+		linkBaseURL:     strings.TrimSuffix(linkBaseURL, "/"),
+	}
+}
+
+// ForgotPassword mails a password reset link. Unknown emails are ignored so
+// the response does not reveal which addresses have accounts.
+func (s *AccountService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.jwt.GeneratePurposeToken(user.ID, utils.TokenTypePasswordReset, utils.PasswordResetTokenDuration)
+	if err != nil {
+		return errors.New("failed to generate reset token")
+	}
+
+	return s.mailer.Send(ctx, domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password for your account.\n\n"+
+			"Open this link within %s to choose a new password:\n%s\n\n"+
+			"If this wasn't you, you can ignore this email.\n",
+			utils.PasswordResetTokenDuration, s.link("/reset-password", token)),
+	})
+}
+
+// ResetPassword sets a new password, signs the user out everywhere and
+// revokes their API keys, since a reset usually means the account was
+// compromised
+func (s *AccountService) ResetPassword(ctx context.Context, req domain.ResetPasswordRequest) error {
+	claims, err := s.redeem(ctx, req.Token, utils.TokenTypePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+	if _, err := s.userRepo.UpdateById(ctx, claims.Subject, map[string]any{"password_hash": hash}); err != nil {
+		return invalidTokenIfNotFound(err)
+	}
+
+	now := time.Now()
+	if err := revokeUserAPIKeys(ctx, s.apiKeyRepo, claims.Subject, now); err != nil {
+		return err
+	}
+	return s.revocationStore.RevokeSubject(ctx, claims.Subject, now)
+}
+
+// SendVerificationEmail mails an email verification link to the user
+func (s *AccountService) SendVerificationEmail(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return notFound(err, "user not found")
+	}
+	if user.EmailVerified {
+		return domain.NewConflictError("email already verified")
+	}
+
+	token, err := s.jwt.GeneratePurposeToken(user.ID, utils.TokenTypeEmailVerification, utils.EmailVerificationTokenDuration)
+	if err != nil {
+		return errors.New("failed to generate verification token")
+	}
+
+	return s.mailer.Send(ctx, domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Please confirm your email address by opening this link within %s:\n%s\n",
+			utils.EmailVerificationTokenDuration, s.link("/verify-email", token)),
+	})
+}
+
+// VerifyEmail marks the token's user as having a confirmed email address
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.redeem(ctx, token, utils.TokenTypeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.UpdateById(ctx, claims.Subject, map[string]any{"email_verified": true}); err != nil {
+		return invalidTokenIfNotFound(err)
+	}
+	return nil
+}
+
+// redeem validates a mailed token and revokes it so it cannot be used again.
+// Tokens issued before the user's tokens were revoked in bulk, e.g. by a
+// password reset, are invalid too.
+func (s *AccountService) redeem(ctx context.Context, token string, tokenType utils.TokenType) (*utils.Claims, error) {
+	claims, err := s.jwt.ValidateToken(token, tokenType)
+	if err != nil {
+		return nil, errInvalidAccountToken
+	}
+
+	revoked, err := revocation.IsTokenRevoked(ctx, s.revocationStore, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errInvalidAccountToken
+	}
+
+	// Claiming the jti lets only one of concurrent redemptions through
+	claimed, err := s.revocationStore.RevokeOnce(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errInvalidAccountToken
+	}
+	return claims, nil
+}
+
+// invalidTokenIfNotFound reports a token whose user no longer exists as
+// invalid
+func invalidTokenIfNotFound(err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return errInvalidAccountToken
+	}
+	return err
+}
+
+// link builds a frontend URL carrying the token
+func (s *AccountService) link(path, token string) string {
+	return s.linkBaseURL + path + "?token=" + url.QueryEscape(token)
+}
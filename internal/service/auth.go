@@ -1,50 +1,310 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"go-gin-boilerplate/internal/domain"
 	"go-gin-boilerplate/internal/port"
 	"go-gin-boilerplate/internal/utils"
+	"log/slog"
+	"strings"
+	"time"
 )
 
 type AuthService struct {
-	jwt *utils.JWT
+	jwt             *utils.JWT
+	userRepo        port.UserRepository
+	apiKeyRepo      port.APIKeyRepository
+	refreshStore    port.RefreshTokenStore
+	revocationStore port.TokenRevocationStore
+	sessionStore    port.SessionStore
 }
 
-func NewAuthService(jwt *utils.JWT) port.AuthService {
+func NewAuthService(jwt *utils.JWT, userRepo port.UserRepository, apiKeyRepo port.APIKeyRepository, refreshStore port.RefreshTokenStore, revocationStore port.TokenRevocationStore, sessionStore port.SessionStore) port.AuthService {
 	return &AuthService{
-		jwt: jwt,
+		jwt:             jwt,
+		userRepo:        userRepo,
+		apiKeyRepo:      apiKeyRepo,
+		refreshStore:    refreshStore,
+		revocationStore: revocationStore,
+		sessionStore:    sessionStore,
 	}
 }
 
-// Login is a mock implementation that validates against hardcoded users
-func (s *AuthService) Login(req domain.LoginRequest) (domain.LoginResponse, error) {
-	// Mock users for testing
-	mockUsers := map[string]string{
-		"admin@example.com": "password123",
-		"user@example.com":  "password456",
-		"test@example.com":  "test123",
+// Login looks up the user by email and verifies the password against the
+// stored hash. Users with MFA enabled receive an MFA challenge instead of tokens.
+func (s *AuthService) Login(ctx context.Context, req domain.LoginRequest) (domain.LoginResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return domain.LoginResponse{}, err
 	}
+	if err != nil || !utils.CheckPassword(user.PasswordHash, req.Password) {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid email or password")
+	}
+
+	return s.IssueTokens(ctx, user)
+}
 
-	// Check if user exists and password is correct
-	if password, exists := mockUsers[req.Email]; !exists || password != req.Password {
-		return domain.LoginResponse{}, errors.New("invalid email or password")
+// Register creates a new user with a hashed password
+func (s *AuthService) Register(ctx context.Context, req domain.RegisterRequest) (domain.RegisterResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" {
+		return domain.RegisterResponse{}, domain.NewValidationError("email is required", domain.FieldError{Field: "email", Message: "is required"})
+	}
+
+	_, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return domain.RegisterResponse{}, domain.NewConflictError("email already registered")
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return domain.RegisterResponse{}, err
 	}
 
-	// Generate real JWT tokens
-	accessToken, err := s.jwt.GenerateAccessToken(req.Email)
+	hash, err := hashPassword(req.Password)
 	if err != nil {
-		return domain.LoginResponse{}, errors.New("failed to generate access token")
+		return domain.RegisterResponse{}, err
+	}
+
+	user, err := s.userRepo.Create(ctx, &domain.User{
+		Email:        email,
+		PasswordHash: hash,
+		Roles:        []string{domain.RoleUser},
+	})
+	if err != nil {
+		return domain.RegisterResponse{}, err
+	}
+
+	return domain.RegisterResponse{
+		ID:    user.ID,
+		Email: user.Email,
+	}, nil
+}
+
+// Logout ends the session the given refresh token belongs to and, when
+// provided, revokes the access token the client was using
+func (s *AuthService) Logout(ctx context.Context, refreshToken string, accessToken string) error {
+	claims, err := s.jwt.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return domain.NewUnauthorizedError("invalid refresh token")
+	}
+	if err := s.endSession(ctx, claims.Subject, claims.FamilyID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	if accessToken == "" {
+		return nil
+	}
+	accessClaims, err := s.jwt.ValidateAccessToken(accessToken)
+	if err != nil || accessClaims.Subject != claims.Subject {
+		// An expired or foreign access token needs no revocation
+		return nil
+	}
+	return s.revocationStore.Revoke(ctx, accessClaims.ID, accessClaims.ExpiresAt.Time)
+}
+
+// RevokeUserTokens invalidates every access and refresh token and every API
+// key issued to the user up to now
+func (s *AuthService) RevokeUserTokens(ctx context.Context, userID string) error {
+	if strings.TrimSpace(userID) == "" {
+		return domain.NewValidationError("user ID is required")
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return notFound(err, "user not found")
+	}
+	now := time.Now()
+	if err := revokeUserAPIKeys(ctx, s.apiKeyRepo, userID, now); err != nil {
+		return err
+	}
+	return s.revocationStore.RevokeSubject(ctx, userID, now)
+}
+
+// Impersonate lets an administrator act as another user to reproduce an
+// issue. Administrators cannot be impersonated, so the token never carries
+// more privileges than its holder already has.
+func (s *AuthService) Impersonate(ctx context.Context, userID string) (domain.ImpersonationResponse, error) {
+	actor := domain.IdentityFromContext(ctx)
+	if actor == nil || actor.IsDelegated() {
+		return domain.ImpersonationResponse{}, domain.ErrForbidden
+	}
+	if actor.UserID == userID {
+		return domain.ImpersonationResponse{}, domain.NewValidationError("cannot impersonate yourself")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ImpersonationResponse{}, notFound(err, "user not found")
+	}
+	roles := user.EffectiveRoles()
+	if domain.HasRole(roles, domain.RoleAdmin) {
+		return domain.ImpersonationResponse{}, domain.NewForbiddenError("cannot impersonate an administrator")
+	}
+
+	token, claims, err := s.jwt.GenerateImpersonationToken(user.ID, roles, actor.UserID)
+	if err != nil {
+		return domain.ImpersonationResponse{}, errors.New("failed to generate access token")
+	}
+	slog.Info("impersonation token issued",
+		"actor", actor.UserID,
+		"subject", user.ID,
+		"jti", claims.ID,
+		"expires_at", claims.ExpiresAt.Time)
+
+	return domain.ImpersonationResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(claims.ExpiresAt.Time).Round(time.Second).Seconds()),
+		UserID:      user.ID,
+		ActorID:     actor.UserID,
+	}, nil
+}
+
+// RefreshToken rotates a refresh token: the presented token is consumed and a
+// new access/refresh pair in the same family is issued. Presenting a token
+// that was already rotated revokes the whole family.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (domain.LoginResponse, error) {
+	claims, err := s.jwt.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid refresh token")
 	}
 
-	refreshToken, err := s.jwt.GenerateRefreshToken(req.Email)
+	revoked, err := s.refreshStore.IsFamilyRevoked(ctx, claims.FamilyID)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	if !revoked {
+		revoked, err = s.revocationStore.IsSubjectRevoked(ctx, claims.Subject, claims.IssuedAt.Time)
+		if err != nil {
+			return domain.LoginResponse{}, err
+		}
+	}
+	if revoked {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid refresh token")
+	}
+
+	record, err := s.refreshStore.MarkUsed(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			if revokeErr := s.endSession(ctx, claims.Subject, claims.FamilyID, claims.ExpiresAt.Time); revokeErr != nil {
+				return domain.LoginResponse{}, revokeErr
+			}
+			return domain.LoginResponse{}, err
+		}
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid refresh token")
+	}
+	if record.UserID != claims.Subject || record.FamilyID != claims.FamilyID {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid refresh token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.Subject)
+	if err != nil {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid refresh token")
+	}
+
+	return s.issueTokens(ctx, user, claims.FamilyID)
+}
+
+// IssueTokens starts a new token family for a user who passed a first factor,
+// by password or with an external identity provider. With MFA enabled that
+// only earns a challenge for the second step.
+func (s *AuthService) IssueTokens(ctx context.Context, user *domain.User) (domain.LoginResponse, error) {
+	if user.MFAEnabled {
+		mfaToken, err := s.jwt.GenerateMFAToken(user.ID)
+		if err != nil {
+			return domain.LoginResponse{}, errors.New("failed to generate MFA token")
+		}
+		return domain.LoginResponse{Email: user.Email, MFARequired: true, MFAToken: mfaToken}, nil
+	}
+	return s.issueTokens(ctx, user, "")
+}
+
+// IssueMFATokens starts a new token family for a user who also passed the
+// second factor
+func (s *AuthService) IssueMFATokens(ctx context.Context, user *domain.User) (domain.LoginResponse, error) {
+	return s.issueTokens(ctx, user, "")
+}
+
+// issueTokens generates a new access/refresh token pair for the user and
+// records the refresh token in the given family (a new one if empty)
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, familyID string) (domain.LoginResponse, error) {
+	refreshToken, claims, err := s.jwt.GenerateRefreshToken(user.ID, familyID)
 	if err != nil {
 		return domain.LoginResponse{}, errors.New("failed to generate refresh token")
 	}
 
+	accessToken, err := s.jwt.GenerateSessionAccessToken(user.ID, user.EffectiveRoles(), claims.FamilyID)
+	if err != nil {
+		return domain.LoginResponse{}, errors.New("failed to generate access token")
+	}
+
+	err = s.refreshStore.Save(ctx, &domain.RefreshTokenRecord{
+		JTI:       claims.ID,
+		FamilyID:  claims.FamilyID,
+		UserID:    user.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return domain.LoginResponse{}, errors.New("failed to store refresh token")
+	}
+	if err := s.saveSession(ctx, user.ID, claims); err != nil {
+		return domain.LoginResponse{}, errors.New("failed to store session")
+	}
+
 	return domain.LoginResponse{
-		Email:        req.Email,
+		Email:        user.Email,
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}, nil
 }
+
+// saveSession records the session of a newly issued refresh token, keeping
+// the creation time of an existing session
+func (s *AuthService) saveSession(ctx context.Context, userID string, claims *utils.Claims) error {
+	now := time.Now()
+	session, err := s.sessionStore.Get(ctx, claims.FamilyID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrSessionNotFound) {
+			return err
+		}
+		session = &domain.Session{ID: claims.FamilyID, UserID: userID, CreatedAt: now}
+	}
+
+	if client := domain.ClientInfoFromContext(ctx); client != (domain.ClientInfo{}) {
+		session.IP = client.IP
+		session.UserAgent = client.UserAgent
+		session.Device = client.Device
+	}
+	session.LastUsedAt = now
+	session.ExpiresAt = claims.ExpiresAt.Time
+	return s.sessionStore.Save(ctx, session)
+}
+
+// endSession revokes a refresh token family together with the access tokens
+// issued in it and forgets the session
+func (s *AuthService) endSession(ctx context.Context, userID, familyID string, expiresAt time.Time) error {
+	if err := s.refreshStore.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	if err := s.revocationStore.Revoke(ctx, familyID, expiresAt); err != nil {
+		return err
+	}
+	return s.sessionStore.Delete(ctx, userID, familyID)
+}
+
+// hashPassword hashes a new password. Passwords within the 72 character limit
+// of requests can still exceed bcrypt's 72 bytes with multibyte characters,
+// which is a validation error rather than a failure.
+func hashPassword(password string) (string, error) {
+	hash, err := utils.HashPassword(password)
+	if errors.Is(err, utils.ErrPasswordTooLong) {
+		return "", domain.NewValidationError("request validation failed", domain.FieldError{
+			Field:   "password",
+			Message: fmt.Sprintf("must be at most %d bytes", utils.MaxPasswordBytes),
+		})
+	}
+	if err != nil {
+		return "", errors.New("failed to hash password")
+	}
+	return hash, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"sync"
+)
+
+// Rule reports whether the identity (nil for anonymous callers) may perform
+// an action on the resource
+type Rule func(ctx context.Context, identity *domain.Identity, resource any) bool
+
+// PolicyAuthorizer is a registry of Go rules per action. An action is allowed
+// if any of its rules allows it; actions without rules are denied when
+// denyByDefault is set and allowed otherwise.
+type PolicyAuthorizer struct {
+	mu            sync.RWMutex
+	rules         map[domain.Action][]Rule
+	denyByDefault bool
+}
+
+func NewPolicyAuthorizer(denyByDefault bool) *PolicyAuthorizer {
+	return &PolicyAuthorizer{
+		rules:         make(map[domain.Action][]Rule),
+		denyByDefault: denyByDefault,
+	}
+}
+
+// Register adds rules for an action
+func (a *PolicyAuthorizer) Register(action domain.Action, rules ...Rule) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rules[action] = append(a.rules[action], rules...)
+}
+
+func (a *PolicyAuthorizer) Authorize(ctx context.Context, action domain.Action, resource any) error {
+	a.mu.RLock()
+	rules, declared := a.rules[action]
+	a.mu.RUnlock()
+
+	if !declared {
+		if a.denyByDefault {
+			return domain.ErrForbidden
+		}
+		return nil
+	}
+
+	identity := domain.IdentityFromContext(ctx)
+	for _, rule := range rules {
+		if rule(ctx, identity, resource) {
+			return nil
+		}
+	}
+	return domain.ErrForbidden
+}
+
+// Anyone allows every caller, including anonymous ones
+func Anyone() Rule {
+	return func(context.Context, *domain.Identity, any) bool {
+		return true
+	}
+}
+
+// Authenticated allows any caller with an identity
+func Authenticated() Rule {
+	return func(_ context.Context, identity *domain.Identity, _ any) bool {
+		return identity != nil
+	}
+}
+
+// HasRole allows callers with any of the given roles
+func HasRole(roles ...string) Rule {
+	return func(_ context.Context, identity *domain.Identity, _ any) bool {
+		return identity != nil && domain.HasRole(identity.Roles, roles...)
+	}
+}
+
+// IsOwner allows the caller whose user ID equals the resource owner returned by ownerOf
+func IsOwner(ownerOf func(resource any) string) Rule {
+	return func(_ context.Context, identity *domain.Identity, resource any) bool {
+		return identity != nil && resource != nil && ownerOf(resource) == identity.UserID
+	}
+}
+
+// RegisterDefaultPolicies declares the built-in policies for Bar and Foo
+func RegisterDefaultPolicies(a *PolicyAuthorizer) {
+	barOwner := IsOwner(func(resource any) string {
+		if bar, ok := resource.(*domain.Bar); ok {
+			return bar.OwnerID
+		}
+		return ""
+	})
+
+	a.Register(domain.ActionBarCreate, Authenticated())
+	a.Register(domain.ActionBarRead, Authenticated())
+	a.Register(domain.ActionBarUpdate, barOwner, HasRole(domain.RoleAdmin))
+	a.Register(domain.ActionBarDelete, barOwner, HasRole(domain.RoleAdmin))
+
+	a.Register(domain.ActionFooRead, Anyone())
+	a.Register(domain.ActionFooCreate, Authenticated())
+	a.Register(domain.ActionFooUpdate, Authenticated())
+	a.Register(domain.ActionFooDelete, HasRole(domain.RoleAdmin))
+}
@@ -2,24 +2,35 @@ package service
 
 import (
 	"context"
-	"errors"
 	"go-gin-boilerplate/internal/domain"
 	"go-gin-boilerplate/internal/port"
+	"maps"
+	"slices"
 	"strings"
 )
 
+// barUpdatableFields are the fields clients may change, by their JSON names.
+// Identity and ownership are left out, and so is any other spelling of a
+// field, e.g. the Go name "OwnerID", which the database would also accept.
+var barUpdatableFields = map[string]bool{"name": true, "description": true, "status": true}
+
 type BarService struct {
-	barRepo port.BarRepository
+	barRepo    port.BarRepository
+	authorizer port.Authorizer
 }
 
-func NewBarService(barRepo port.BarRepository) port.BarService {
-	return &BarService{barRepo: barRepo}
+func NewBarService(barRepo port.BarRepository, authorizer port.Authorizer) port.BarService {
+	return &BarService{barRepo: barRepo, authorizer: authorizer}
 }
 
 func (bs *BarService) Create(ctx context.Context, bar *domain.Bar) (*domain.Bar, error) {
 	// Validate required fields
 	if strings.TrimSpace(bar.Name) == "" {
-		return nil, errors.New("bar name is required")
+		return nil, domain.NewValidationError("bar name is required", domain.FieldError{Field: "name", Message: "is required"})
+	}
+
+	if err := bs.authorizer.Authorize(ctx, domain.ActionBarCreate, bar); err != nil {
+		return nil, err
 	}
 
 	// Set default status if not provided
@@ -27,36 +38,85 @@ func (bs *BarService) Create(ctx context.Context, bar *domain.Bar) (*domain.Bar,
 		bar.Status = "active"
 	}
 
+	// The creator owns the bar
+	bar.OwnerID = ""
+	if identity := domain.IdentityFromContext(ctx); identity != nil {
+		bar.OwnerID = identity.UserID
+	}
+
 	return bs.barRepo.Create(ctx, bar)
 }
 
 func (bs *BarService) GetAll(ctx context.Context) ([]*domain.Bar, error) {
+	if err := bs.authorizer.Authorize(ctx, domain.ActionBarRead, nil); err != nil {
+		return nil, err
+	}
 	return bs.barRepo.GetAll(ctx)
 }
 
+// List returns a page of the bar items matching the query
+func (bs *BarService) List(ctx context.Context, query domain.ListQuery) (domain.Page[*domain.Bar], error) {
+	if err := bs.authorizer.Authorize(ctx, domain.ActionBarRead, nil); err != nil {
+		return domain.Page[*domain.Bar]{}, err
+	}
+	query, err := checkListQuery(query, domain.BarListFields)
+	if err != nil {
+		return domain.Page[*domain.Bar]{}, err
+	}
+	return bs.barRepo.List(ctx, query)
+}
+
 func (bs *BarService) GetByID(ctx context.Context, id string) (*domain.Bar, error) {
 	if strings.TrimSpace(id) == "" {
-		return nil, errors.New("bar ID is required")
+		return nil, domain.NewValidationError("bar ID is required")
+	}
+	bar, err := bs.barRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, "bar not found")
+	}
+	if err := bs.authorizer.Authorize(ctx, domain.ActionBarRead, bar); err != nil {
+		return nil, err
 	}
-	return bs.barRepo.GetByID(ctx, id)
+	return bar, nil
 }
 
 func (bs *BarService) GetByName(ctx context.Context, name string) (*domain.Bar, error) {
 	if strings.TrimSpace(name) == "" {
-		return nil, errors.New("bar name is required")
+		return nil, domain.NewValidationError("bar name is required")
+	}
+	bar, err := bs.barRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, notFound(err, "bar not found")
+	}
+	if err := bs.authorizer.Authorize(ctx, domain.ActionBarRead, bar); err != nil {
+		return nil, err
 	}
-	return bs.barRepo.GetByName(ctx, name)
+	return bar, nil
 }
 
 func (bs *BarService) UpdateById(ctx context.Context, id string, update map[string]any) (*domain.Bar, error) {
 	if strings.TrimSpace(id) == "" {
-		return nil, errors.New("bar ID is required")
+		return nil, domain.NewValidationError("bar ID is required")
 	}
 
 	// Check if bar exists
-	_, err := bs.barRepo.GetByID(ctx, id)
+	bar, err := bs.barRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("bar not found")
+		return nil, notFound(err, "bar not found")
+	}
+
+	if err := bs.authorizer.Authorize(ctx, domain.ActionBarUpdate, bar); err != nil {
+		return nil, err
+	}
+
+	var fieldErrs []domain.FieldError
+	for _, field := range slices.Sorted(maps.Keys(update)) {
+		if !barUpdatableFields[field] {
+			fieldErrs = append(fieldErrs, domain.FieldError{Field: field, Message: "cannot be updated"})
+		}
+	}
+	if len(fieldErrs) > 0 {
+		return nil, domain.NewValidationError("request validation failed", fieldErrs...)
 	}
 
 	return bs.barRepo.UpdateById(ctx, id, update)
@@ -64,13 +124,17 @@ func (bs *BarService) UpdateById(ctx context.Context, id string, update map[stri
 
 func (bs *BarService) DeleteById(ctx context.Context, id string) error {
 	if strings.TrimSpace(id) == "" {
-		return errors.New("bar ID is required")
+		return domain.NewValidationError("bar ID is required")
 	}
 
 	// Check if bar exists
-	_, err := bs.barRepo.GetByID(ctx, id)
+	bar, err := bs.barRepo.GetByID(ctx, id)
 	if err != nil {
-		return errors.New("bar not found")
+		return notFound(err, "bar not found")
+	}
+
+	if err := bs.authorizer.Authorize(ctx, domain.ActionBarDelete, bar); err != nil {
+		return err
 	}
 
 	return bs.barRepo.DeleteById(ctx, id)
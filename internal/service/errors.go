@@ -0,0 +1,15 @@
+package service
+
+import (
+	"errors"
+	"go-gin-boilerplate/internal/domain"
+)
+
+// notFound gives a not-found error from a repository a message naming the
+// missing entity. Other errors, e.g. database outages, are returned as is.
+func notFound(err error, message string) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.WrapError(domain.ErrNotFound, message, err)
+	}
+	return err
+}
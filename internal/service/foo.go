@@ -2,56 +2,93 @@ package service
 
 import (
 	"context"
-	"errors"
 	"go-gin-boilerplate/internal/domain"
 	"go-gin-boilerplate/internal/port"
 	"strings"
 )
 
 type FooService struct {
-	fooRepo port.FooRepository
+	fooRepo    port.FooRepository
+	authorizer port.Authorizer
 }
 
-func NewFooService(fooRepo port.FooRepository) port.FooService {
-	return &FooService{fooRepo: fooRepo}
+func NewFooService(fooRepo port.FooRepository, authorizer port.Authorizer) port.FooService {
+	return &FooService{fooRepo: fooRepo, authorizer: authorizer}
 }
 
 func (fs *FooService) Create(ctx context.Context, foo *domain.Foo) (*domain.Foo, error) {
 	// Validate required fields
 	if strings.TrimSpace(foo.Name) == "" {
-		return nil, errors.New("foo name is required")
+		return nil, domain.NewValidationError("foo name is required", domain.FieldError{Field: "name", Message: "is required"})
+	}
+
+	if err := fs.authorizer.Authorize(ctx, domain.ActionFooCreate, foo); err != nil {
+		return nil, err
 	}
 
 	return fs.fooRepo.Create(ctx, foo)
 }
 
 func (fs *FooService) GetAll(ctx context.Context) ([]*domain.Foo, error) {
+	if err := fs.authorizer.Authorize(ctx, domain.ActionFooRead, nil); err != nil {
+		return nil, err
+	}
 	return fs.fooRepo.GetAll(ctx)
 }
 
+// List returns a page of the foo items matching the query
+func (fs *FooService) List(ctx context.Context, query domain.ListQuery) (domain.Page[*domain.Foo], error) {
+	if err := fs.authorizer.Authorize(ctx, domain.ActionFooRead, nil); err != nil {
+		return domain.Page[*domain.Foo]{}, err
+	}
+	query, err := checkListQuery(query, domain.FooListFields)
+	if err != nil {
+		return domain.Page[*domain.Foo]{}, err
+	}
+	return fs.fooRepo.List(ctx, query)
+}
+
 func (fs *FooService) GetByID(ctx context.Context, id string) (*domain.Foo, error) {
 	if strings.TrimSpace(id) == "" {
-		return nil, errors.New("foo ID is required")
+		return nil, domain.NewValidationError("foo ID is required")
+	}
+	foo, err := fs.fooRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, "foo not found")
+	}
+	if err := fs.authorizer.Authorize(ctx, domain.ActionFooRead, foo); err != nil {
+		return nil, err
 	}
-	return fs.fooRepo.GetByID(ctx, id)
+	return foo, nil
 }
 
 func (fs *FooService) GetByName(ctx context.Context, name string) (*domain.Foo, error) {
 	if strings.TrimSpace(name) == "" {
-		return nil, errors.New("foo name is required")
+		return nil, domain.NewValidationError("foo name is required")
+	}
+	foo, err := fs.fooRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, notFound(err, "foo not found")
+	}
+	if err := fs.authorizer.Authorize(ctx, domain.ActionFooRead, foo); err != nil {
+		return nil, err
 	}
-	return fs.fooRepo.GetByName(ctx, name)
+	return foo, nil
 }
 
 func (fs *FooService) UpdateById(ctx context.Context, id string, update map[string]any) (*domain.Foo, error) {
 	if strings.TrimSpace(id) == "" {
-		return nil, errors.New("foo ID is required")
+		return nil, domain.NewValidationError("foo ID is required")
 	}
 
 	// Check if foo exists
-	_, err := fs.fooRepo.GetByID(ctx, id)
+	foo, err := fs.fooRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("foo not found")
+		return nil, notFound(err, "foo not found")
+	}
+
+	if err := fs.authorizer.Authorize(ctx, domain.ActionFooUpdate, foo); err != nil {
+		return nil, err
 	}
 
 	return fs.fooRepo.UpdateById(ctx, id, update)
@@ -59,13 +96,17 @@ func (fs *FooService) UpdateById(ctx context.Context, id string, update map[stri
 
 func (fs *FooService) DeleteById(ctx context.Context, id string) error {
 	if strings.TrimSpace(id) == "" {
-		return errors.New("foo ID is required")
+		return domain.NewValidationError("foo ID is required")
 	}
 
 	// Check if foo exists
-	_, err := fs.fooRepo.GetByID(ctx, id)
+	foo, err := fs.fooRepo.GetByID(ctx, id)
 	if err != nil {
-		return errors.New("foo not found")
+		return notFound(err, "foo not found")
+	}
+
+	if err := fs.authorizer.Authorize(ctx, domain.ActionFooDelete, foo); err != nil {
+		return err
 	}
 
 	return fs.fooRepo.DeleteById(ctx, id)
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"go-gin-boilerplate/internal/domain"
+	"slices"
+)
+
+// checkListQuery rejects filters and sort orders on fields outside the given
+// ones and applies the default and maximum page size
+func checkListQuery(query domain.ListQuery, fields []string) (domain.ListQuery, error) {
+	var invalid []domain.FieldError
+	for _, filter := range query.Filters {
+		if !slices.Contains(fields, filter.Field) {
+			invalid = append(invalid, domain.FieldError{Field: filter.Field, Message: "cannot be filtered by"})
+		}
+	}
+	for _, sort := range query.Sort {
+		if !slices.Contains(fields, sort.Field) {
+			invalid = append(invalid, domain.FieldError{Field: "sort", Message: "cannot sort by " + sort.Field})
+		}
+	}
+	if query.Offset < 0 {
+		invalid = append(invalid, domain.FieldError{Field: "offset", Message: "must not be negative"})
+	}
+	if query.Offset > 0 && query.Cursor != "" {
+		invalid = append(invalid, domain.FieldError{Field: "cursor", Message: "cannot be combined with offset"})
+	}
+	if len(invalid) > 0 {
+		return query, domain.NewValidationError("invalid list query", invalid...)
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = domain.DefaultListLimit
+	}
+	query.Limit = min(query.Limit, domain.MaxListLimit)
+	return query, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"strings"
+	"time"
+)
+
+// maxMFAChallengeFailures is the number of wrong codes an MFA challenge token
+// accepts before it is revoked and the password has to be entered again
+const maxMFAChallengeFailures = 3
+
+type LoginThrottler struct {
+	store  port.LoginAttemptStore
+	policy domain.LoginThrottlePolicy
+}
+
+func NewLoginThrottler(store port.LoginAttemptStore, policy domain.LoginThrottlePolicy) port.LoginThrottler {
+	return &LoginThrottler{store: store, policy: policy}
+}
+
+// Attempt counts a login attempt against the IP and the account before the
+// credentials are checked. It refuses locked IPs and accounts, locks those
+// going over their limit, and refuses accounts that retry before the delay
+// earned by their previous attempts has passed.
+func (t *LoginThrottler) Attempt(ctx context.Context, email, ip string) error {
+	now := time.Now()
+
+	if _, err := t.attempt(ctx, ipThrottleKey(ip), t.policy.IPMaxAttempts, now); err != nil {
+		return err
+	}
+	attempts, err := t.attempt(ctx, accountThrottleKey(email), t.policy.MaxAttempts, now)
+	if err != nil {
+		return err
+	}
+
+	// Retrying early is not a guess, but it restarts the wait
+	if previous := attempts.Count - 1; previous > 0 {
+		if wait := t.delay(previous); attempts.LastAttempt.Add(wait).After(now) {
+			if err := t.Forgive(ctx, email, ip); err != nil {
+				return err
+			}
+			return &domain.LoginThrottledError{RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+// attempt counts an attempt against key. Attempts on a locked key are refused
+// without being counted; going over maxAttempts locks the key.
+func (t *LoginThrottler) attempt(ctx context.Context, key string, maxAttempts int, now time.Time) (domain.LoginAttempts, error) {
+	attempts, err := t.store.RecordAttempt(ctx, key, now, t.policy.Window)
+	if err != nil {
+		return domain.LoginAttempts{}, err
+	}
+	if attempts.LockedUntil.After(now) {
+		if err := t.store.Forgive(ctx, key); err != nil {
+			return domain.LoginAttempts{}, err
+		}
+		return domain.LoginAttempts{}, &domain.LoginThrottledError{RetryAfter: attempts.LockedUntil.Sub(now)}
+	}
+	if attempts.Count > maxAttempts {
+		if err := t.store.Lock(ctx, key, now.Add(t.policy.LockoutDuration)); err != nil {
+			return domain.LoginAttempts{}, err
+		}
+		return domain.LoginAttempts{}, &domain.LoginThrottledError{RetryAfter: t.policy.LockoutDuration}
+	}
+	return attempts, nil
+}
+
+// Forgive uncounts an attempt of the account and the IP that did not fail
+func (t *LoginThrottler) Forgive(ctx context.Context, email, ip string) error {
+	if err := t.store.Forgive(ctx, accountThrottleKey(email)); err != nil {
+		return err
+	}
+	return t.store.Forgive(ctx, ipThrottleKey(ip))
+}
+
+// RecordMFAFailure counts a wrong second factor against the challenge it was
+// entered for; Attempt already counted it against the account and the IP
+func (t *LoginThrottler) RecordMFAFailure(ctx context.Context, challengeID string) (bool, error) {
+	attempts, err := t.store.RecordAttempt(ctx, mfaChallengeThrottleKey(challengeID), time.Now(), t.policy.Window)
+	if err != nil {
+		return false, err
+	}
+	return attempts.Count >= maxMFAChallengeFailures, nil
+}
+
+// RecordSuccess clears the account's attempts and uncounts the successful
+// attempt of the IP. The rest of the IP count is kept so a client cannot
+// reset it by logging in to an account of its own.
+func (t *LoginThrottler) RecordSuccess(ctx context.Context, email, ip string) error {
+	if err := t.store.Reset(ctx, accountThrottleKey(email)); err != nil {
+		return err
+	}
+	return t.store.Forgive(ctx, ipThrottleKey(ip))
+}
+
+// delay returns the wait before the next attempt after the given number of
+// attempts that did not succeed
+func (t *LoginThrottler) delay(attempts int) time.Duration {
+	delay := t.policy.BaseDelay
+	for i := 1; i < attempts && delay < t.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, t.policy.MaxDelay)
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+func mfaChallengeThrottleKey(challengeID string) string {
+	return "mfa:" + challengeID
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/utils"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recoveryCodeCount is the number of recovery codes issued at enrollment
+const recoveryCodeCount = 10
+
+// usedRecoveryCodeTTL is how long a used recovery code stays claimed in the
+// revocation store, which keeps it unusable even if its removal from the user
+// was lost to a concurrent update. Re-enrolling replaces all codes.
+const usedRecoveryCodeTTL = 365 * 24 * time.Hour
+
+// errInvalidMFACode is returned for wrong, expired or replayed codes
+var errInvalidMFACode = domain.NewValidationError("invalid MFA code")
+
+type MFAService struct {
+	jwt             *utils.JWT
+	userRepo        port.UserRepository
+	revocationStore port.TokenRevocationStore
+	authSvc         port.AuthService
+	throttler       port.LoginThrottler
+	issuer          string
+}
+
+func NewMFAService(jwt *utils.JWT, userRepo port.UserRepository, revocationStore port.TokenRevocationStore, authSvc port.AuthService, throttler port.LoginThrottler, issuer string) port.MFAService {
+	return &MFAService{
+		jwt:             jwt,
+		userRepo:        userRepo,
+		revocationStore: revocationStore,
+		authSvc:         authSvc,
+		throttler:       throttler,
+		issuer:          issuer,
+	}
+}
+
+// Enroll generates a TOTP secret and recovery codes for the caller. Enrolling
+// again before activation replaces the pending secret.
+func (s *MFAService) Enroll(ctx context.Context) (domain.MFAEnrollResponse, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return domain.MFAEnrollResponse{}, err
+	}
+	if user.MFAEnabled {
+		return domain.MFAEnrollResponse{}, domain.NewConflictError("MFA is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return domain.MFAEnrollResponse{}, err
+	}
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := utils.RandomID(5)
+		if err != nil {
+			return domain.MFAEnrollResponse{}, err
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	_, err = s.userRepo.UpdateById(ctx, user.ID, map[string]any{
+		"mfa_secret":         secret,
+		"mfa_recovery_codes": hashes,
+		"mfa_last_step":      int64(0),
+	})
+	if err != nil {
+		return domain.MFAEnrollResponse{}, err
+	}
+
+	return domain.MFAEnrollResponse{
+		Secret:        secret,
+		OTPAuthURI:    utils.TOTPURI(s.issuer, user.Email, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Activate turns MFA on once the user proves their authenticator produces
+// valid codes for the enrolled secret
+func (s *MFAService) Activate(ctx context.Context, code string) error {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if user.MFAEnabled {
+		return domain.NewConflictError("MFA is already enabled")
+	}
+	if user.MFASecret == "" {
+		return domain.NewValidationError("MFA is not enrolled")
+	}
+
+	step, ok := utils.ValidateTOTP(user.MFASecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return errInvalidMFACode
+	}
+
+	_, err = s.userRepo.UpdateById(ctx, user.ID, map[string]any{
+		"mfa_enabled":   true,
+		"mfa_last_step": step,
+	})
+	return err
+}
+
+// Disable turns MFA off after checking a current TOTP or recovery code
+func (s *MFAService) Disable(ctx context.Context, code string) error {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return domain.NewValidationError("MFA is not enabled")
+	}
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+
+	_, err = s.userRepo.UpdateById(ctx, user.ID, map[string]any{
+		"mfa_enabled":        false,
+		"mfa_secret":         "",
+		"mfa_recovery_codes": []string{},
+		"mfa_last_step":      int64(0),
+	})
+	return err
+}
+
+// Verify completes a two-step login. The challenge token is single use and is
+// revoked after a few wrong codes; every verification also counts as a login
+// attempt of the account and the client IP, so the login throttler bounds
+// guessing.
+func (s *MFAService) Verify(ctx context.Context, req domain.MFAVerifyRequest) (domain.LoginResponse, error) {
+	claims, err := s.jwt.ValidateMFAToken(req.MFAToken)
+	if err != nil {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid MFA token")
+	}
+	revoked, err := s.revocationStore.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	if revoked {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid MFA token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.Subject)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return domain.LoginResponse{}, err
+	}
+	if err != nil || !user.MFAEnabled {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid MFA token")
+	}
+
+	ip := domain.ClientInfoFromContext(ctx).IP
+	if err := s.throttler.Attempt(ctx, user.Email, ip); err != nil {
+		return domain.LoginResponse{}, err
+	}
+	factor, ok := matchSecondFactor(user, req.Code)
+	if !ok {
+		exhausted, err := s.throttler.RecordMFAFailure(ctx, claims.ID)
+		if err != nil {
+			return domain.LoginResponse{}, err
+		}
+		if exhausted {
+			if err := s.revocationStore.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+				return domain.LoginResponse{}, err
+			}
+		}
+		// A wrong code fails the login rather than a request to change settings
+		return domain.LoginResponse{}, domain.NewUnauthorizedError(errInvalidMFACode.Error())
+	}
+
+	// Only one of concurrent verifications of the challenge goes on to use up
+	// the code and issue tokens
+	claimed, err := s.revocationStore.RevokeOnce(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	if !claimed {
+		return domain.LoginResponse{}, domain.NewUnauthorizedError("invalid MFA token")
+	}
+	if err := s.consumeSecondFactor(ctx, user, factor); err != nil {
+		if !errors.Is(err, errInvalidMFACode) {
+			return domain.LoginResponse{}, err
+		}
+		// The code was used by another login in the meantime
+		return domain.LoginResponse{}, domain.NewUnauthorizedError(errInvalidMFACode.Error())
+	}
+	if err := s.throttler.RecordSuccess(ctx, user.Email, ip); err != nil {
+		return domain.LoginResponse{}, err
+	}
+	return s.authSvc.IssueMFATokens(ctx, user)
+}
+
+// currentUser loads the calling user; delegated credentials such as API keys
+// cannot manage MFA
+func (s *MFAService) currentUser(ctx context.Context) (*domain.User, error) {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil || identity.IsDelegated() {
+		return nil, domain.ErrForbidden
+	}
+	user, err := s.userRepo.GetByID(ctx, identity.UserID)
+	if err != nil {
+		return nil, notFound(err, "user not found")
+	}
+	return user, nil
+}
+
+// secondFactor is a code that matched one of the user's second factors: a
+// TOTP step or the hash of a recovery code
+type secondFactor struct {
+	step         int64
+	recoveryHash string
+}
+
+// matchSecondFactor checks a TOTP code newer than the last one used, or one
+// of the user's recovery codes, without using it up
+func matchSecondFactor(user *domain.User, code string) (secondFactor, bool) {
+	code = strings.TrimSpace(code)
+
+	if step, ok := utils.ValidateTOTP(user.MFASecret, code, time.Now()); ok {
+		return secondFactor{step: step}, step > user.MFALastStep
+	}
+
+	hash := hashRecoveryCode(code)
+	for _, stored := range user.MFARecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(stored)) == 1 {
+			return secondFactor{recoveryHash: hash}, true
+		}
+	}
+	return secondFactor{}, false
+}
+
+// consumeSecondFactor uses up a matched code. Codes are claimed in the
+// revocation store first, so concurrent requests cannot use one code twice.
+func (s *MFAService) consumeSecondFactor(ctx context.Context, user *domain.User, factor secondFactor) error {
+	if factor.recoveryHash == "" {
+		if err := s.claimCode(ctx, "totp:"+user.ID+":"+strconv.FormatInt(factor.step, 10), utils.TOTPStepExpiry(factor.step)); err != nil {
+			return err
+		}
+		_, err := s.userRepo.UpdateById(ctx, user.ID, map[string]any{"mfa_last_step": factor.step})
+		return err
+	}
+
+	if err := s.claimCode(ctx, "recovery:"+user.ID+":"+factor.recoveryHash, time.Now().Add(usedRecoveryCodeTTL)); err != nil {
+		return err
+	}
+	// Remove the code from the current codes, not the ones loaded before the
+	// claim, to keep the removal of other codes in the meantime
+	current, err := s.userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	remaining := slices.DeleteFunc(slices.Clone(current.MFARecoveryCodes), func(stored string) bool { return stored == factor.recoveryHash })
+	_, err = s.userRepo.UpdateById(ctx, user.ID, map[string]any{"mfa_recovery_codes": remaining})
+	return err
+}
+
+// verifySecondFactor accepts and uses up a TOTP or recovery code
+func (s *MFAService) verifySecondFactor(ctx context.Context, user *domain.User, code string) error {
+	factor, ok := matchSecondFactor(user, code)
+	if !ok {
+		return errInvalidMFACode
+	}
+	return s.consumeSecondFactor(ctx, user, factor)
+}
+
+// claimCode consumes a single-use code, rejecting it if it was used already
+func (s *MFAService) claimCode(ctx context.Context, key string, expiresAt time.Time) error {
+	claimed, err := s.revocationStore.RevokeOnce(ctx, "mfa_code:"+key, expiresAt)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return errInvalidMFACode
+	}
+	return nil
+}
+
+// hashRecoveryCode returns the hex SHA-256 of a recovery code, ignoring case
+// and the separator
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
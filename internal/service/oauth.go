@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/revocation"
+	"go-gin-boilerplate/internal/utils"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type OAuthService struct {
+	jwt             *utils.JWT
+	clientRepo      port.OAuthClientRepository
+	codeStore       port.AuthorizationCodeStore
+	userRepo        port.UserRepository
+	revocationStore port.TokenRevocationStore
+}
+
+func NewOAuthService(jwt *utils.JWT, clientRepo port.OAuthClientRepository, codeStore port.AuthorizationCodeStore, userRepo port.UserRepository, revocationStore port.TokenRevocationStore) port.OAuthService {
+	return &OAuthService{
+		jwt:             jwt,
+		clientRepo:      clientRepo,
+		codeStore:       codeStore,
+		userRepo:        userRepo,
+		revocationStore: revocationStore,
+	}
+}
+
+// RegisterClient registers a new OAuth2 client. The client's scopes must be
+// permissions the registering user's roles grant.
+func (s *OAuthService) RegisterClient(ctx context.Context, req domain.RegisterOAuthClientRequest) (domain.RegisterOAuthClientResponse, error) {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil || identity.IsDelegated() {
+		return domain.RegisterOAuthClientResponse{}, domain.ErrForbidden
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return domain.RegisterOAuthClientResponse{}, domain.NewValidationError("client name is required", domain.FieldError{Field: "name", Message: "is required"})
+	}
+	for _, grantType := range req.GrantTypes {
+		switch grantType {
+		case domain.GrantTypeClientCredentials:
+			if req.Public {
+				return domain.RegisterOAuthClientResponse{}, domain.NewValidationError("public clients cannot use client_credentials")
+			}
+		case domain.GrantTypeAuthorizationCode:
+			if len(req.RedirectURIs) == 0 {
+				return domain.RegisterOAuthClientResponse{}, domain.NewValidationError("redirect_uris are required for authorization_code")
+			}
+		default:
+			return domain.RegisterOAuthClientResponse{}, domain.NewValidationError("unsupported grant type: " + grantType)
+		}
+	}
+	for _, redirectURI := range req.RedirectURIs {
+		parsed, err := url.Parse(redirectURI)
+		if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+			return domain.RegisterOAuthClientResponse{}, domain.NewValidationError("invalid redirect URI: " + redirectURI)
+		}
+	}
+	for _, scope := range req.Scopes {
+		if !domain.HasPermission(identity.Roles, domain.Permission(scope)) {
+			return domain.RegisterOAuthClientResponse{}, domain.NewForbiddenError("scope not permitted: " + scope)
+		}
+	}
+
+	client := &domain.OAuthClient{
+		Name:         strings.TrimSpace(req.Name),
+		Public:       req.Public,
+		RedirectURIs: req.RedirectURIs,
+		GrantTypes:   req.GrantTypes,
+		Scopes:       req.Scopes,
+		CreatedBy:    identity.UserID,
+		CreatedAt:    time.Now(),
+	}
+
+	var secret string
+	if !req.Public {
+		var err error
+		if secret, err = utils.RandomID(32); err != nil {
+			return domain.RegisterOAuthClientResponse{}, err
+		}
+		client.SecretHash = hashClientSecret(secret)
+	}
+
+	created, err := s.clientRepo.Create(ctx, client)
+	if err != nil {
+		return domain.RegisterOAuthClientResponse{}, err
+	}
+
+	return domain.RegisterOAuthClientResponse{ClientSecret: secret, Client: created}, nil
+}
+
+func (s *OAuthService) ListClients(ctx context.Context) ([]*domain.OAuthClient, error) {
+	return s.clientRepo.GetAll(ctx)
+}
+
+// DeleteClient removes a client. Tokens already issued to it stay valid until
+// they expire.
+func (s *OAuthService) DeleteClient(ctx context.Context, id string) error {
+	if _, err := s.clientRepo.GetByID(ctx, id); err != nil {
+		return notFound(err, "oauth client not found")
+	}
+	return s.clientRepo.DeleteById(ctx, id)
+}
+
+// Authorize issues a short-lived authorization code for the signed-in user.
+// Consent is implied by the user calling this endpoint with their own token.
+func (s *OAuthService) Authorize(ctx context.Context, req domain.AuthorizeRequest) (string, error) {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil || identity.IsDelegated() {
+		return "", domain.ErrForbidden
+	}
+
+	if req.ResponseType != "code" {
+		return "", fmt.Errorf("%w: response_type must be code", domain.ErrOAuthInvalidRequest)
+	}
+	client, err := s.clientRepo.GetByID(ctx, req.ClientID)
+	if err != nil {
+		return "", fmt.Errorf("%w: unknown client", domain.ErrOAuthInvalidClient)
+	}
+	if !client.AllowsGrant(domain.GrantTypeAuthorizationCode) {
+		return "", fmt.Errorf("%w: authorization_code is not allowed for this client", domain.ErrOAuthUnauthorizedClient)
+	}
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return "", fmt.Errorf("%w: redirect_uri is not registered", domain.ErrOAuthInvalidRequest)
+	}
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != "S256" {
+		return "", fmt.Errorf("%w: code_challenge_method must be S256", domain.ErrOAuthInvalidRequest)
+	}
+	if client.Public && req.CodeChallenge == "" {
+		return "", fmt.Errorf("%w: public clients must use PKCE", domain.ErrOAuthInvalidRequest)
+	}
+
+	scopes, err := grantScopes(client, req.Scope, func(scope string) bool {
+		return domain.HasPermission(identity.Roles, domain.Permission(scope))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	code, err := utils.RandomID(32)
+	if err != nil {
+		return "", err
+	}
+	err = s.codeStore.Save(ctx, code, &domain.AuthorizationCode{
+		ClientID:      client.ID,
+		UserID:        identity.UserID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+	})
+	if err != nil {
+		return "", errors.New("failed to store authorization code")
+	}
+
+	redirect, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid redirect_uri", domain.ErrOAuthInvalidRequest)
+	}
+	query := redirect.Query()
+	query.Set("code", code)
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirect.RawQuery = query.Encode()
+	return redirect.String(), nil
+}
+
+// Token handles the token endpoint for the supported grant types
+func (s *OAuthService) Token(ctx context.Context, req domain.TokenRequest) (domain.TokenResponse, error) {
+	switch req.GrantType {
+	case domain.GrantTypeClientCredentials:
+		return s.clientCredentials(ctx, req)
+	case domain.GrantTypeAuthorizationCode:
+		return s.authorizationCode(ctx, req)
+	default:
+		return domain.TokenResponse{}, domain.ErrOAuthUnsupportedGrantType
+	}
+}
+
+// clientCredentials issues a token to a confidential client acting on its own behalf
+func (s *OAuthService) clientCredentials(ctx context.Context, req domain.TokenRequest) (domain.TokenResponse, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return domain.TokenResponse{}, err
+	}
+	if client.Public || !client.AllowsGrant(domain.GrantTypeClientCredentials) {
+		return domain.TokenResponse{}, domain.ErrOAuthUnauthorizedClient
+	}
+
+	scopes, err := grantScopes(client, req.Scope, nil)
+	if err != nil {
+		return domain.TokenResponse{}, err
+	}
+
+	token, claims, err := s.jwt.GenerateClientAccessToken(client.ID, "", nil, scopes)
+	if err != nil {
+		return domain.TokenResponse{}, errors.New("failed to generate access token")
+	}
+	return newTokenResponse(token, claims), nil
+}
+
+// authorizationCode redeems a code issued by Authorize for a token acting on
+// behalf of the user who approved it
+func (s *OAuthService) authorizationCode(ctx context.Context, req domain.TokenRequest) (domain.TokenResponse, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return domain.TokenResponse{}, err
+	}
+	if !client.AllowsGrant(domain.GrantTypeAuthorizationCode) {
+		return domain.TokenResponse{}, domain.ErrOAuthUnauthorizedClient
+	}
+
+	authCode, err := s.codeStore.Consume(ctx, req.Code)
+	if err != nil {
+		return domain.TokenResponse{}, fmt.Errorf("%w: invalid or expired code", domain.ErrOAuthInvalidGrant)
+	}
+	if authCode.ClientID != client.ID || authCode.RedirectURI != req.RedirectURI {
+		return domain.TokenResponse{}, fmt.Errorf("%w: code was issued to another client or redirect_uri", domain.ErrOAuthInvalidGrant)
+	}
+	if authCode.CodeChallenge != "" && utils.PKCEChallenge(req.CodeVerifier) != authCode.CodeChallenge {
+		return domain.TokenResponse{}, fmt.Errorf("%w: code_verifier does not match", domain.ErrOAuthInvalidGrant)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, authCode.UserID)
+	if err != nil {
+		return domain.TokenResponse{}, fmt.Errorf("%w: user not found", domain.ErrOAuthInvalidGrant)
+	}
+
+	token, claims, err := s.jwt.GenerateClientAccessToken(client.ID, user.ID, user.EffectiveRoles(), authCode.Scopes)
+	if err != nil {
+		return domain.TokenResponse{}, errors.New("failed to generate access token")
+	}
+	return newTokenResponse(token, claims), nil
+}
+
+// Introspect reports whether token is an active access token issued by this
+// service. Only confidential clients may introspect.
+func (s *OAuthService) Introspect(ctx context.Context, clientID, clientSecret, token string) (domain.IntrospectionResponse, error) {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return domain.IntrospectionResponse{}, err
+	}
+	if client.Public {
+		return domain.IntrospectionResponse{}, fmt.Errorf("%w: public clients cannot introspect tokens", domain.ErrOAuthInvalidClient)
+	}
+
+	claims, err := s.jwt.ValidateAccessToken(token)
+	if err != nil {
+		return domain.IntrospectionResponse{Active: false}, nil
+	}
+	revoked, err := revocation.IsTokenRevoked(ctx, s.revocationStore, claims)
+	if err != nil {
+		return domain.IntrospectionResponse{}, err
+	}
+	if revoked {
+		return domain.IntrospectionResponse{Active: false}, nil
+	}
+
+	return domain.IntrospectionResponse{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		TokenType: "Bearer",
+		Exp:       claims.ExpiresAt.Unix(),
+		Iat:       claims.IssuedAt.Unix(),
+		Sub:       claims.Subject,
+		Aud:       claims.Audience,
+		Iss:       claims.Issuer,
+		Jti:       claims.ID,
+	}, nil
+}
+
+// authenticateClient looks the client up and, for confidential clients,
+// verifies the secret
+func (s *OAuthService) authenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("%w: client authentication required", domain.ErrOAuthInvalidClient)
+	}
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: client authentication failed", domain.ErrOAuthInvalidClient)
+	}
+	if client.Public {
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(hashClientSecret(clientSecret)), []byte(client.SecretHash)) != 1 {
+		return nil, fmt.Errorf("%w: client authentication failed", domain.ErrOAuthInvalidClient)
+	}
+	return client, nil
+}
+
+// grantScopes resolves the space-separated requested scope against the
+// client's registered scopes and, if allowed is set, a further restriction.
+// An empty request grants every scope that passes both.
+func grantScopes(client *domain.OAuthClient, requested string, allowed func(scope string) bool) ([]string, error) {
+	registered := make(map[string]bool, len(client.Scopes))
+	for _, scope := range client.Scopes {
+		registered[scope] = true
+	}
+
+	var scopes []string
+	if fields := strings.Fields(requested); len(fields) > 0 {
+		for _, scope := range fields {
+			if !registered[scope] || (allowed != nil && !allowed(scope)) {
+				return nil, fmt.Errorf("%w: scope not permitted: %s", domain.ErrOAuthInvalidScope, scope)
+			}
+			scopes = append(scopes, scope)
+		}
+		return scopes, nil
+	}
+
+	for _, scope := range client.Scopes {
+		if allowed == nil || allowed(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: no scope can be granted", domain.ErrOAuthInvalidScope)
+	}
+	return scopes, nil
+}
+
+func newTokenResponse(token string, claims *utils.Claims) domain.TokenResponse {
+	return domain.TokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(claims.ExpiresAt.Sub(claims.IssuedAt.Time).Seconds()),
+		Scope:       claims.Scope,
+	}
+}
+
+// hashClientSecret returns the hex SHA-256 of a client secret; secrets are
+// random and long, so a fast hash is sufficient
+func hashClientSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
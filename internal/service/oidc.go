@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/utils"
+	"strings"
+	"time"
+)
+
+// errUnverifiedLocalAccount refuses linking an external account to a local
+// account whose owner has not proven control of the email address
+var errUnverifiedLocalAccount = domain.NewConflictError("an account with this email exists; verify its email before signing in with this provider")
+
+type OIDCService struct {
+	providers    map[string]*utils.OIDCProvider
+	stateStore   port.OIDCStateStore
+	identityRepo port.ExternalIdentityRepository
+	userRepo     port.UserRepository
+	authSvc      port.AuthService
+	unitOfWork   port.UnitOfWork
+}
+
+func NewOIDCService(providers map[string]*utils.OIDCProvider, stateStore port.OIDCStateStore, identityRepo port.ExternalIdentityRepository, userRepo port.UserRepository, authSvc port.AuthService, unitOfWork port.UnitOfWork) port.OIDCService {
+	return &OIDCService{
+		providers:    providers,
+		stateStore:   stateStore,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authSvc:      authSvc,
+		unitOfWork:   unitOfWork,
+	}
+}
+
+// AuthorizationURL starts an authorization code flow with PKCE. The state,
+// nonce and code verifier are kept server-side until the callback.
+func (s *OIDCService) AuthorizationURL(ctx context.Context, provider string) (string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", domain.ErrOIDCProviderNotFound
+	}
+
+	state, err := utils.RandomID(16)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := utils.RandomID(16)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := utils.RandomID(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.stateStore.Save(ctx, state, &domain.OIDCAuthState{
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+	})
+	if err != nil {
+		return "", errors.New("failed to store oidc state")
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, utils.PKCEChallenge(verifier))
+	if err != nil {
+		return "", domain.WrapError(domain.ErrUpstream, "oidc provider discovery failed", err)
+	}
+	return authURL, nil
+}
+
+// Callback redeems the authorization code, validates the ID token and signs
+// the linked local user in. Users with MFA enabled get an MFA challenge, as
+// with a password login.
+func (s *OIDCService) Callback(ctx context.Context, provider, code, state string) (domain.LoginResponse, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return domain.LoginResponse{}, domain.ErrOIDCProviderNotFound
+	}
+
+	authState, err := s.stateStore.Consume(ctx, state)
+	if err != nil {
+		return domain.LoginResponse{}, domain.ErrOIDCStateInvalid
+	}
+	if authState.Provider != provider {
+		return domain.LoginResponse{}, domain.ErrOIDCStateInvalid
+	}
+
+	rawIDToken, err := p.Exchange(ctx, code, authState.CodeVerifier)
+	if err != nil {
+		return domain.LoginResponse{}, domain.WrapError(domain.ErrUnauthorized, "failed to exchange authorization code", err)
+	}
+	claims, err := p.VerifyIDToken(ctx, rawIDToken, authState.Nonce)
+	if err != nil {
+		return domain.LoginResponse{}, domain.WrapError(domain.ErrUnauthorized, "invalid id token", err)
+	}
+
+	user, err := s.resolveUser(ctx, provider, claims)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+
+	return s.authSvc.IssueTokens(ctx, user)
+}
+
+// resolveUser returns the user linked to the external account. Unlinked
+// accounts are linked to the local user with the same verified email, or a
+// new passwordless user is provisioned. Local users who have not verified
+// their email are not linked: whoever registered the address could still
+// sign in with their password.
+func (s *OIDCService) resolveUser(ctx context.Context, provider string, claims *utils.IDTokenClaims) (*domain.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.WrapError(domain.ErrUnauthorized, "linked user not found", err)
+			}
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	// Linking on an unverified email would let anyone claim an existing account
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" || !claims.EmailVerified {
+		return nil, domain.NewUnauthorizedError("verified email is required")
+	}
+
+	// A provisioned user is only kept together with the link to the external
+	// account, so a failed link can be retried by logging in again
+	var user *domain.User
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				return err
+			}
+			user, err = s.userRepo.Create(ctx, &domain.User{
+				Email:         email,
+				EmailVerified: true,
+				Roles:         []string{domain.RoleUser},
+			})
+			if err != nil {
+				return err
+			}
+		} else if !user.EmailVerified {
+			return errUnverifiedLocalAccount
+		}
+
+		_, err = s.identityRepo.Create(ctx, &domain.ExternalIdentity{
+			UserID:    user.ID,
+			Provider:  provider,
+			Subject:   claims.Subject,
+			Email:     email,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return errors.New("failed to link external identity")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"sort"
+)
+
+type SessionService struct {
+	sessionStore    port.SessionStore
+	refreshStore    port.RefreshTokenStore
+	revocationStore port.TokenRevocationStore
+}
+
+func NewSessionService(sessionStore port.SessionStore, refreshStore port.RefreshTokenStore, revocationStore port.TokenRevocationStore) port.SessionService {
+	return &SessionService{
+		sessionStore:    sessionStore,
+		refreshStore:    refreshStore,
+		revocationStore: revocationStore,
+	}
+}
+
+// List returns the caller's live sessions, most recently used first. Sessions
+// ended by other means, e.g. revoking all of the user's tokens, are left out.
+func (s *SessionService) List(ctx context.Context) ([]domain.Session, error) {
+	identity, err := sessionOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.sessionStore.ListByUser(ctx, identity.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]domain.Session, 0, len(stored))
+	for _, session := range stored {
+		revoked, err := s.refreshStore.IsFamilyRevoked(ctx, session.ID)
+		if err == nil && !revoked {
+			revoked, err = s.revocationStore.IsSubjectRevoked(ctx, session.UserID, session.LastUsedAt)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			continue
+		}
+		session.Current = session.ID == identity.SessionID
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+// Revoke ends one of the caller's sessions. The refresh token family can no
+// longer be rotated and AuthMiddleware rejects its access tokens.
+func (s *SessionService) Revoke(ctx context.Context, id string) error {
+	identity, err := sessionOwner(ctx)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.sessionStore.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if session.UserID != identity.UserID {
+		return domain.ErrSessionNotFound
+	}
+
+	if err := s.refreshStore.RevokeFamily(ctx, session.ID); err != nil {
+		return err
+	}
+	if err := s.revocationStore.Revoke(ctx, session.ID, session.ExpiresAt); err != nil {
+		return err
+	}
+	return s.sessionStore.Delete(ctx, session.UserID, session.ID)
+}
+
+// sessionOwner returns the calling user; delegated credentials such as API
+// keys cannot manage sessions
+func sessionOwner(ctx context.Context) (*domain.Identity, error) {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil || identity.IsDelegated() {
+		return nil, domain.ErrForbidden
+	}
+	return identity, nil
+}
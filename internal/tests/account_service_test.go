@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/mailer"
+	"go-gin-boilerplate/internal/service"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMailer records sent messages
+type fakeMailer struct {
+	sent []domain.EmailMessage
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg domain.EmailMessage) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// tokenFromMail extracts the token query parameter from the link in a message
+func tokenFromMail(t *testing.T, msg domain.EmailMessage) string {
+	link := regexp.MustCompile(`https?://\S+`).FindString(msg.Body)
+	require.NotEmpty(t, link)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	return parsed.Query().Get("token")
+}
+
+func TestAccountService_PasswordReset(t *testing.T) {
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", PasswordHash: hash},
+	}}
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	mail := &fakeMailer{}
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	accountSvc := service.NewAccountService(jwt, users, newFakeAPIKeyRepo(), revocationStore, mail, "http://app.test/")
+	ctx := context.Background()
+
+	// Unknown addresses are not revealed
+	require.NoError(t, accountSvc.ForgotPassword(ctx, "nobody@example.com"))
+	assert.Empty(t, mail.sent)
+
+	require.NoError(t, accountSvc.ForgotPassword(ctx, "Admin@Example.com"))
+	require.Len(t, mail.sent, 1)
+	assert.Equal(t, "admin@example.com", mail.sent[0].To)
+	assert.Contains(t, mail.sent[0].Body, "http://app.test/reset-password?token=")
+	token := tokenFromMail(t, mail.sent[0])
+	require.NoError(t, accountSvc.ForgotPassword(ctx, "admin@example.com"))
+	otherToken := tokenFromMail(t, mail.sent[1])
+
+	require.NoError(t, accountSvc.ResetPassword(ctx, domain.ResetPasswordRequest{Token: token, Password: "newpassword"}))
+	assert.EqualError(t, accountSvc.ResetPassword(ctx, domain.ResetPasswordRequest{Token: token, Password: "another"}), "invalid or expired token")
+	// The reset also invalidates the other links mailed before it
+	assert.EqualError(t, accountSvc.ResetPassword(ctx, domain.ResetPasswordRequest{Token: otherToken, Password: "another"}), "invalid or expired token")
+
+	_, err = authSvc.Login(ctx, domain.LoginRequest{Email: "admin@example.com", Password: "password123"})
+	assert.Error(t, err)
+	_, err = authSvc.Login(ctx, domain.LoginRequest{Email: "admin@example.com", Password: "newpassword"})
+	assert.NoError(t, err)
+
+	// A verification token cannot be used to reset a password
+	verifyToken, err := jwt.GeneratePurposeToken("1", utils.TokenTypeEmailVerification, utils.EmailVerificationTokenDuration)
+	require.NoError(t, err)
+	assert.Error(t, accountSvc.ResetPassword(ctx, domain.ResetPasswordRequest{Token: verifyToken, Password: "another"}))
+}
+
+func TestAccountService_VerifyEmail(t *testing.T) {
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com"},
+	}}
+	mail := &fakeMailer{}
+	accountSvc := service.NewAccountService(newTestJWT(), users, newFakeAPIKeyRepo(), cache.NewMemoryRevocationStore(), mail, "http://app.test")
+	ctx := context.Background()
+
+	require.NoError(t, accountSvc.SendVerificationEmail(ctx, "1"))
+	require.Len(t, mail.sent, 1)
+	token := tokenFromMail(t, mail.sent[0])
+
+	require.NoError(t, accountSvc.VerifyEmail(ctx, token))
+	assert.True(t, users.users["1"].EmailVerified)
+	assert.EqualError(t, accountSvc.VerifyEmail(ctx, token), "invalid or expired token")
+	assert.EqualError(t, accountSvc.SendVerificationEmail(ctx, "1"), "email already verified")
+}
+
+func TestAccountService_ConcurrentRedeemUsesTokenOnce(t *testing.T) {
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com"},
+	}}
+	jwt := newTestJWT()
+	accountSvc := service.NewAccountService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRevocationStore(), &fakeMailer{}, "http://app.test")
+	token, err := jwt.GeneratePurposeToken("1", utils.TokenTypePasswordReset, utils.PasswordResetTokenDuration)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var redeemed atomic.Int32
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if accountSvc.ResetPassword(context.Background(), domain.ResetPasswordRequest{Token: token, Password: "newpassword"}) == nil {
+				redeemed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), redeemed.Load())
+}
+
+func TestLogMailer_WritesMessageFile(t *testing.T) {
+	dir := t.TempDir()
+	m := mailer.NewLogMailer("no-reply@example.com", dir)
+
+	require.NoError(t, m.Send(context.Background(), domain.EmailMessage{
+		To:      "admin@example.com",
+		Subject: "Hello\r\nBcc: attacker@example.com",
+		Body:    "body",
+	}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: admin@example.com")
+	assert.NotContains(t, string(content), "\r\nBcc:")
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/middleware"
+	"go-gin-boilerplate/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPIKeyRepo keeps API keys in memory
+type fakeAPIKeyRepo struct {
+	keys map[string]*domain.APIKey
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{keys: make(map[string]*domain.APIKey)}
+}
+
+func (r *fakeAPIKeyRepo) Create(_ context.Context, apiKey *domain.APIKey) (*domain.APIKey, error) {
+	apiKey.ID = apiKey.Prefix
+	r.keys[apiKey.ID] = apiKey
+	return apiKey, nil
+}
+func (r *fakeAPIKeyRepo) GetByID(_ context.Context, id string) (*domain.APIKey, error) {
+	if apiKey, ok := r.keys[id]; ok {
+		return apiKey, nil
+	}
+	return nil, domain.NewNotFoundError("entity not found")
+}
+func (r *fakeAPIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	return r.GetByID(ctx, prefix)
+}
+func (r *fakeAPIKeyRepo) GetAllByUserID(_ context.Context, userID string) ([]*domain.APIKey, error) {
+	var apiKeys []*domain.APIKey
+	for _, apiKey := range r.keys {
+		if apiKey.UserID == userID {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	return apiKeys, nil
+}
+func (r *fakeAPIKeyRepo) UpdateById(ctx context.Context, id string, update map[string]any) (*domain.APIKey, error) {
+	apiKey, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if at, ok := update["revoked_at"].(time.Time); ok {
+		apiKey.RevokedAt = &at
+	}
+	if at, ok := update["last_used_at"].(time.Time); ok {
+		apiKey.LastUsedAt = &at
+	}
+	return apiKey, nil
+}
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	users := new(MockUserRepo)
+	users.On("GetByID", context.Background(), "1").Return(&domain.User{ID: "1", Roles: []string{domain.RoleUser}}, nil)
+	svc := service.NewAPIKeyService(newFakeAPIKeyRepo(), users)
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+
+	created, err := svc.Create(ctx, domain.CreateAPIKeyRequest{Name: "batch", Scopes: []string{"bar:read"}})
+	require.NoError(t, err)
+	assert.Contains(t, created.Key, domain.APIKeyPrefix+"_")
+	assert.NotContains(t, created.APIKey.KeyHash, created.Key)
+
+	identity, err := svc.Authenticate(context.Background(), created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "1", identity.UserID)
+	assert.True(t, identity.Can(domain.PermissionBarRead))
+	assert.False(t, identity.Can(domain.PermissionBarWrite))
+
+	_, err = svc.Authenticate(context.Background(), created.Key+"x")
+	assert.Error(t, err)
+
+	// Keys cannot mint keys, nor grant scopes the caller lacks
+	_, err = svc.Create(domain.WithIdentity(context.Background(), identity), domain.CreateAPIKeyRequest{Name: "x", Scopes: []string{"bar:read"}})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	_, err = svc.Create(ctx, domain.CreateAPIKeyRequest{Name: "x", Scopes: []string{"bar:delete"}})
+	assert.Error(t, err)
+
+	keys, err := svc.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	require.NoError(t, svc.Revoke(ctx, created.APIKey.ID))
+	_, err = svc.Authenticate(context.Background(), created.Key)
+	assert.Error(t, err)
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := new(MockUserRepo)
+	users.On("GetByID", context.Background(), "1").Return(&domain.User{ID: "1", Roles: []string{domain.RoleUser}}, nil)
+	svc := service.NewAPIKeyService(newFakeAPIKeyRepo(), users)
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+	created, err := svc.Create(ctx, domain.CreateAPIKeyRequest{Name: "batch", Scopes: []string{"bar:read"}})
+	require.NoError(t, err)
+
+	router := gin.New()
+	auth := middleware.AuthMiddleware(newTestJWT(), cache.NewMemoryRevocationStore(), svc)
+	router.GET("/read", auth, middleware.RequirePermission(domain.PermissionBarRead), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("userID"))
+	})
+	router.GET("/write", auth, middleware.RequirePermission(domain.PermissionBarWrite), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("/read", created.Key)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Body.String())
+	assert.Equal(t, http.StatusForbidden, request("/write", created.Key).Code)
+	assert.Equal(t, http.StatusUnauthorized, request("/read", "ggb_bad_key").Code)
+}
+
+func TestAPIKeyService_KeysCannotManageKeys(t *testing.T) {
+	users := new(MockUserRepo)
+	users.On("GetByID", context.Background(), "1").Return(&domain.User{ID: "1", Roles: []string{domain.RoleUser}}, nil)
+	svc := service.NewAPIKeyService(newFakeAPIKeyRepo(), users)
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+	created, err := svc.Create(ctx, domain.CreateAPIKeyRequest{Name: "batch", Scopes: []string{"bar:read"}})
+	require.NoError(t, err)
+
+	identity, err := svc.Authenticate(context.Background(), created.Key)
+	require.NoError(t, err)
+	keyCtx := domain.WithIdentity(context.Background(), identity)
+	_, err = svc.List(keyCtx)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.ErrorIs(t, svc.Revoke(keyCtx, created.APIKey.ID), domain.ErrForbidden)
+}
+
+func TestAPIKeys_RevokedWithUserTokens(t *testing.T) {
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", Roles: []string{domain.RoleUser}},
+	}}
+	apiKeyRepo := newFakeAPIKeyRepo()
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	apiKeySvc := service.NewAPIKeyService(apiKeyRepo, users)
+	authSvc := service.NewAuthService(jwt, users, apiKeyRepo, cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	mail := &fakeMailer{}
+	accountSvc := service.NewAccountService(jwt, users, apiKeyRepo, revocationStore, mail, "http://app.test")
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+
+	created, err := apiKeySvc.Create(ctx, domain.CreateAPIKeyRequest{Name: "batch", Scopes: []string{"bar:read"}})
+	require.NoError(t, err)
+	require.NoError(t, accountSvc.ForgotPassword(ctx, "admin@example.com"))
+	require.NoError(t, accountSvc.ResetPassword(ctx, domain.ResetPasswordRequest{Token: tokenFromMail(t, mail.sent[0]), Password: "newpassword"}))
+	_, err = apiKeySvc.Authenticate(context.Background(), created.Key)
+	assert.Error(t, err)
+
+	created, err = apiKeySvc.Create(ctx, domain.CreateAPIKeyRequest{Name: "batch", Scopes: []string{"bar:read"}})
+	require.NoError(t, err)
+	_, err = apiKeySvc.Authenticate(context.Background(), created.Key)
+	require.NoError(t, err)
+	require.NoError(t, authSvc.RevokeUserTokens(ctx, "1"))
+	_, err = apiKeySvc.Authenticate(context.Background(), created.Key)
+	assert.Error(t, err)
+}
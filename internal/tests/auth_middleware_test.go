@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/config"
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/middleware"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func performAuthRequest(router *gin.Engine, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthMiddleware_Revocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwt := newTestJWT()
+	store := cache.NewMemoryRevocationStore()
+	ctx := context.Background()
+
+	router := gin.New()
+	router.GET("/protected", middleware.AuthMiddleware(jwt, store, nil), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("userID"))
+	})
+
+	token, err := jwt.GenerateAccessToken("1", nil)
+	assert.NoError(t, err)
+
+	w := performAuthRequest(router, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, performAuthRequest(router, "").Code)
+
+	claims, err := jwt.ValidateAccessToken(token)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Revoke(ctx, claims.ID, claims.ExpiresAt.Time))
+	assert.Equal(t, http.StatusUnauthorized, performAuthRequest(router, token).Code)
+
+	other, err := jwt.GenerateAccessToken("2", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, performAuthRequest(router, other).Code)
+
+	assert.NoError(t, store.RevokeSubject(ctx, "2", time.Now()))
+	assert.Equal(t, http.StatusUnauthorized, performAuthRequest(router, other).Code)
+}
+
+func TestRevocationStore_SubjectCutoffBoundary(t *testing.T) {
+	store := cache.NewMemoryRevocationStore()
+	ctx := context.Background()
+	at := time.Date(2026, 1, 1, 12, 0, 0, int(500*time.Millisecond), time.UTC)
+	assert.NoError(t, store.RevokeSubject(ctx, "1", at))
+
+	// Times compare in whole seconds, so tokens issued within the second of
+	// the revocation count as revoked, even after it
+	for issuedAt, revoked := range map[time.Time]bool{
+		at.Add(-time.Second):           true,
+		at.Truncate(time.Second):       true,
+		at.Add(400 * time.Millisecond): true,
+		at.Add(500 * time.Millisecond): false,
+		at.Add(time.Minute):            false,
+	} {
+		got, err := store.IsSubjectRevoked(ctx, "1", issuedAt)
+		assert.NoError(t, err)
+		assert.Equal(t, revoked, got, issuedAt)
+	}
+}
+
+func TestAuthMiddleware_RejectsRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwt := newTestJWT()
+
+	router := gin.New()
+	router.GET("/protected", middleware.AuthMiddleware(jwt, cache.NewMemoryRevocationStore(), nil), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	refreshToken, _, err := jwt.GenerateRefreshToken("1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, performAuthRequest(router, refreshToken).Code)
+
+	// Tokens minted for another audience are not accepted either
+	foreign, err := utils.NewJWT(&config.JWTConfig{
+		Secret:          "test-secret",
+		AccessDuration:  time.Minute,
+		RefreshDuration: time.Hour,
+		Issuer:          "test-issuer",
+		Audience:        "other-service",
+	})
+	assert.NoError(t, err)
+	foreignToken, err := foreign.GenerateAccessToken("1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, performAuthRequest(router, foreignToken).Code)
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwt := newTestJWT()
+
+	router := gin.New()
+	router.GET("/protected", middleware.AuthMiddleware(jwt, cache.NewMemoryRevocationStore(), nil),
+		middleware.RequirePermission(domain.PermissionBarDelete), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+	userToken, err := jwt.GenerateAccessToken("1", []string{domain.RoleUser})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, performAuthRequest(router, userToken).Code)
+
+	adminToken, err := jwt.GenerateAccessToken("2", []string{domain.RoleAdmin})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, performAuthRequest(router, adminToken).Code)
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwt := newTestJWT()
+
+	router := gin.New()
+	router.GET("/protected", middleware.AuthMiddleware(jwt, cache.NewMemoryRevocationStore(), nil),
+		middleware.RequireRole(domain.RoleAdmin), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+	userToken, err := jwt.GenerateAccessToken("1", []string{domain.RoleUser})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, performAuthRequest(router, userToken).Code)
+
+	adminToken, err := jwt.GenerateAccessToken("2", []string{domain.RoleAdmin})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, performAuthRequest(router, adminToken).Code)
+}
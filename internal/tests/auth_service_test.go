@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/config"
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/service"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserRepo struct {
+	mock.Mock
+}
+
+func (m *MockUserRepo) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+func (m *MockUserRepo) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+func (m *MockUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+func (m *MockUserRepo) UpdateById(ctx context.Context, id string, update map[string]any) (*domain.User, error) {
+	args := m.Called(ctx, id, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func newTestJWT() *utils.JWT {
+	jwt, err := utils.NewJWT(&config.JWTConfig{
+		Secret:          "test-secret",
+		AccessDuration:  15 * time.Minute,
+		RefreshDuration: time.Hour,
+		Issuer:          "test-issuer",
+		Audience:        "test-audience",
+	})
+	if err != nil {
+		panic(err)
+	}
+	return jwt
+}
+
+func TestAuthService_Login(t *testing.T) {
+	repo := new(MockUserRepo)
+	jwt := newTestJWT()
+	svc := service.NewAuthService(jwt, repo, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), cache.NewMemoryRevocationStore(), cache.NewMemorySessionStore())
+	ctx := context.Background()
+
+	hash, err := utils.HashPassword("password123")
+	assert.NoError(t, err)
+	user := &domain.User{ID: "1", Email: "admin@example.com", PasswordHash: hash}
+
+	repo.On("GetByEmail", ctx, "admin@example.com").Return(user, nil)
+	repo.On("GetByEmail", ctx, "missing@example.com").Return(nil, domain.NewNotFoundError("entity not found"))
+
+	resp, err := svc.Login(ctx, domain.LoginRequest{Email: "admin@example.com", Password: "password123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "admin@example.com", resp.Email)
+	assert.NotEmpty(t, resp.RefreshToken)
+	claims, err := jwt.ValidateAccessToken(resp.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", claims.Subject)
+	assert.Equal(t, []string{domain.RoleUser}, claims.Roles)
+
+	_, err = svc.Login(ctx, domain.LoginRequest{Email: "admin@example.com", Password: "wrong"})
+	assert.Error(t, err)
+
+	_, err = svc.Login(ctx, domain.LoginRequest{Email: "missing@example.com", Password: "password123"})
+	assert.Error(t, err)
+}
+
+func TestAuthService_Register(t *testing.T) {
+	repo := new(MockUserRepo)
+	svc := service.NewAuthService(newTestJWT(), repo, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), cache.NewMemoryRevocationStore(), cache.NewMemorySessionStore())
+	ctx := context.Background()
+
+	repo.On("GetByEmail", ctx, "new@example.com").Return(nil, domain.NewNotFoundError("entity not found"))
+	repo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Email == "new@example.com" && utils.CheckPassword(u.PasswordHash, "secret123") &&
+			len(u.Roles) == 1 && u.Roles[0] == domain.RoleUser
+	})).Return(&domain.User{ID: "2", Email: "new@example.com"}, nil)
+
+	resp, err := svc.Register(ctx, domain.RegisterRequest{Email: "New@Example.com", Password: "secret123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", resp.ID)
+	assert.Equal(t, "new@example.com", resp.Email)
+
+	repo.On("GetByEmail", ctx, "taken@example.com").Return(&domain.User{ID: "3", Email: "taken@example.com"}, nil)
+	_, err = svc.Register(ctx, domain.RegisterRequest{Email: "taken@example.com", Password: "secret123"})
+	assert.EqualError(t, err, "email already registered")
+
+	// Multibyte passwords within the character limit can exceed bcrypt's bytes
+	repo.On("GetByEmail", ctx, "long@example.com").Return(nil, domain.NewNotFoundError("entity not found"))
+	_, err = svc.Register(ctx, domain.RegisterRequest{Email: "long@example.com", Password: strings.Repeat("ü", 40)})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestAuthService_RefreshToken(t *testing.T) {
+	repo := new(MockUserRepo)
+	svc := service.NewAuthService(newTestJWT(), repo, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), cache.NewMemoryRevocationStore(), cache.NewMemorySessionStore())
+	ctx := context.Background()
+
+	hash, err := utils.HashPassword("password123")
+	assert.NoError(t, err)
+	user := &domain.User{ID: "1", Email: "admin@example.com", PasswordHash: hash}
+
+	repo.On("GetByEmail", ctx, "admin@example.com").Return(user, nil)
+	repo.On("GetByID", ctx, "1").Return(user, nil)
+
+	login, err := svc.Login(ctx, domain.LoginRequest{Email: "admin@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	rotated, err := svc.RefreshToken(ctx, login.RefreshToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin@example.com", rotated.Email)
+	assert.NotEqual(t, login.RefreshToken, rotated.RefreshToken)
+
+	// Replaying the consumed token is detected and revokes the family
+	_, err = svc.RefreshToken(ctx, login.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+
+	_, err = svc.RefreshToken(ctx, rotated.RefreshToken)
+	assert.Error(t, err)
+
+	_, err = svc.RefreshToken(ctx, "not-a-token")
+	assert.Error(t, err)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	repo := new(MockUserRepo)
+	svc := service.NewAuthService(newTestJWT(), repo, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), cache.NewMemoryRevocationStore(), cache.NewMemorySessionStore())
+	ctx := context.Background()
+
+	hash, err := utils.HashPassword("password123")
+	assert.NoError(t, err)
+	user := &domain.User{ID: "1", Email: "admin@example.com", PasswordHash: hash}
+
+	repo.On("GetByEmail", ctx, "admin@example.com").Return(user, nil)
+	repo.On("GetByID", ctx, "1").Return(user, nil)
+
+	login, err := svc.Login(ctx, domain.LoginRequest{Email: "admin@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, svc.Logout(ctx, login.RefreshToken, login.AccessToken))
+
+	_, err = svc.RefreshToken(ctx, login.RefreshToken)
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBarRepo struct {
+	mock.Mock
+}
+
+func (m *MockBarRepo) Create(ctx context.Context, bar *domain.Bar) (*domain.Bar, error) {
+	args := m.Called(ctx, bar)
+	return args.Get(0).(*domain.Bar), args.Error(1)
+}
+func (m *MockBarRepo) GetAll(ctx context.Context) ([]*domain.Bar, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.Bar), args.Error(1)
+}
+func (m *MockBarRepo) List(ctx context.Context, query domain.ListQuery) (domain.Page[*domain.Bar], error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).(domain.Page[*domain.Bar]), args.Error(1)
+}
+func (m *MockBarRepo) GetByID(ctx context.Context, id string) (*domain.Bar, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Bar), args.Error(1)
+}
+func (m *MockBarRepo) GetByName(ctx context.Context, name string) (*domain.Bar, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Bar), args.Error(1)
+}
+func (m *MockBarRepo) UpdateById(ctx context.Context, id string, update map[string]any) (*domain.Bar, error) {
+	args := m.Called(ctx, id, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Bar), args.Error(1)
+}
+func (m *MockBarRepo) DeleteById(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newDefaultAuthorizer() *service.PolicyAuthorizer {
+	authorizer := service.NewPolicyAuthorizer(true)
+	service.RegisterDefaultPolicies(authorizer)
+	return authorizer
+}
+
+func TestBarService_CreateSetsOwner(t *testing.T) {
+	repo := new(MockBarRepo)
+	svc := service.NewBarService(repo, newDefaultAuthorizer())
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "owner", Roles: []string{domain.RoleUser}})
+
+	repo.On("Create", ctx, mock.MatchedBy(func(b *domain.Bar) bool { return b.OwnerID == "owner" })).
+		Return(&domain.Bar{ID: "1", Name: "A", OwnerID: "owner"}, nil)
+	result, err := svc.Create(ctx, &domain.Bar{Name: "A", OwnerID: "someone-else"})
+	assert.NoError(t, err)
+	assert.Equal(t, "owner", result.OwnerID)
+
+	_, err = svc.Create(context.Background(), &domain.Bar{Name: "A"})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestBarService_UpdateRequiresOwnerOrAdmin(t *testing.T) {
+	repo := new(MockBarRepo)
+	svc := service.NewBarService(repo, newDefaultAuthorizer())
+	bar := &domain.Bar{ID: "1", Name: "A", OwnerID: "owner"}
+	update := map[string]any{"name": "B"}
+
+	repo.On("GetByID", mock.Anything, "1").Return(bar, nil)
+	repo.On("UpdateById", mock.Anything, "1", update).Return(&domain.Bar{ID: "1", Name: "B", OwnerID: "owner"}, nil)
+
+	owner := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "owner", Roles: []string{domain.RoleUser}})
+	result, err := svc.UpdateById(owner, "1", update)
+	assert.NoError(t, err)
+	assert.Equal(t, "B", result.Name)
+
+	admin := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "admin", Roles: []string{domain.RoleAdmin}})
+	_, err = svc.UpdateById(admin, "1", update)
+	assert.NoError(t, err)
+
+	stranger := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "stranger", Roles: []string{domain.RoleUser}})
+	_, err = svc.UpdateById(stranger, "1", update)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.ErrorIs(t, svc.DeleteById(stranger, "1"), domain.ErrForbidden)
+}
+
+func TestBarService_UpdateRejectsProtectedFields(t *testing.T) {
+	repo := new(MockBarRepo)
+	svc := service.NewBarService(repo, newDefaultAuthorizer())
+	repo.On("GetByID", mock.Anything, "1").Return(&domain.Bar{ID: "1", Name: "A", OwnerID: "owner"}, nil)
+	owner := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "owner", Roles: []string{domain.RoleUser}})
+
+	// Go field names reach the same columns as the JSON ones
+	_, err := svc.UpdateById(owner, "1", map[string]any{"name": "B", "OwnerID": "attacker", "owner_id": "attacker", "ID": "2"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	var domainErr *domain.Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "ID", Message: "cannot be updated"},
+		{Field: "OwnerID", Message: "cannot be updated"},
+		{Field: "owner_id", Message: "cannot be updated"},
+	}, domainErr.Fields)
+	repo.AssertNotCalled(t, "UpdateById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPolicyAuthorizer_DenyByDefault(t *testing.T) {
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleAdmin}})
+
+	assert.ErrorIs(t, service.NewPolicyAuthorizer(true).Authorize(ctx, domain.ActionBarRead, nil), domain.ErrForbidden)
+	assert.NoError(t, service.NewPolicyAuthorizer(false).Authorize(ctx, domain.ActionBarRead, nil))
+}
@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/handler"
+	"go-gin-boilerplate/internal/middleware"
+	"go-gin-boilerplate/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) domain.Problem {
+	var problem domain.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	return problem
+}
+
+func TestDomainError_KindAndCause(t *testing.T) {
+	cause := errors.New("mongo: no documents in result")
+	err := domain.WrapError(domain.ErrNotFound, "bar not found", cause)
+
+	assert.EqualError(t, err, "bar not found")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, domain.ErrConflict)
+
+	assert.ErrorIs(t, domain.NewConflictError("email already registered"), domain.ErrConflict)
+	assert.ErrorIs(t, domain.ErrSessionNotFound, domain.ErrNotFound)
+	assert.ErrorIs(t, domain.ErrOAuthInvalidGrant, domain.ErrValidation)
+}
+
+func TestBarHandler_ErrorStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockBarRepo)
+	barHandler := handler.NewBarHandler(service.NewBarService(repo, newDefaultAuthorizer()))
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(false))
+	router.GET("/bar/:id", func(c *gin.Context) {
+		identity := &domain.Identity{UserID: "owner", Roles: []string{domain.RoleUser}}
+		c.Request = c.Request.WithContext(domain.WithIdentity(c.Request.Context(), identity))
+	}, barHandler.GetByID)
+
+	repo.On("GetByID", mock.Anything, "1").Return(&domain.Bar{ID: "1", OwnerID: "other"}, nil)
+	repo.On("GetByID", mock.Anything, "missing").Return(nil, domain.NewNotFoundError("entity not found"))
+	repo.On("GetByID", mock.Anything, "broken").Return(nil, errors.New("connection refused"))
+
+	get := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bar/"+id, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, get("1").Code)
+
+	w := get("missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, domain.ProblemContentType, w.Header().Get("Content-Type"))
+	problem := decodeProblem(t, w)
+	assert.Equal(t, domain.CodeNotFound, problem.Code)
+	assert.Equal(t, "Not Found", problem.Title)
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, "bar not found", problem.Detail)
+	assert.Equal(t, "/bar/missing", problem.Instance)
+
+	// A database outage is no longer reported as a missing bar
+	assert.Equal(t, http.StatusInternalServerError, get("broken").Code)
+}
+
+func TestProblem_BindErrorsListFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockUserRepo)
+	authSvc := service.NewAuthService(newTestJWT(), repo, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), cache.NewMemoryRevocationStore(), cache.NewMemorySessionStore())
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(false))
+	router.POST("/register", handler.NewAuthHandler(authSvc, nil, nil).Register)
+
+	register := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := register(`{"email":"not-an-email","password":"123"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	problem := decodeProblem(t, w)
+	assert.Equal(t, domain.CodeValidation, problem.Code)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "email", Message: "must be a valid email address"},
+		{Field: "password", Message: "must be at least 6 characters long"},
+	}, problem.Errors)
+
+	// bcrypt cannot hash longer passwords
+	w = register(`{"email":"new@example.com","password":"` + strings.Repeat("a", 73) + `"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "password", Message: "must be at most 72 characters long"},
+	}, decodeProblem(t, w).Errors)
+
+	w = register(`{"email":42}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []domain.FieldError{{Field: "email", Message: "must be a string"}}, decodeProblem(t, w).Errors)
+
+	w = register(`{`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, domain.CodeBadRequest, decodeProblem(t, w).Code)
+}
+
+func TestProblem_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwt := newTestJWT()
+
+	router := gin.New()
+	router.GET("/protected", middleware.AuthMiddleware(jwt, cache.NewMemoryRevocationStore(), nil),
+		middleware.RequirePermission(domain.PermissionBarDelete), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+	w := performAuthRequest(router, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, domain.ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, domain.CodeUnauthorized, decodeProblem(t, w).Code)
+
+	userToken, err := jwt.GenerateAccessToken("1", []string{domain.RoleUser})
+	require.NoError(t, err)
+	w = performAuthRequest(router, userToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	problem := decodeProblem(t, w)
+	assert.Equal(t, domain.CodeForbidden, problem.Code)
+	assert.Equal(t, "missing permission: "+string(domain.PermissionBarDelete), problem.Detail)
+	assert.Equal(t, "/protected", problem.Instance)
+}
+
+func TestErrorHandler_RecoversPanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	newRouter := func(hideDetails bool) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler(hideDetails))
+		router.GET("/panic", func(c *gin.Context) {
+			panic("nil map write in bar cache")
+		})
+		router.GET("/fail", func(c *gin.Context) {
+			_ = c.Error(errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+		})
+		router.GET("/written", func(c *gin.Context) {
+			_ = c.Error(errors.New("failed to send email"))
+			c.String(http.StatusCreated, "created")
+		})
+		return router
+	}
+	get := func(router *gin.Engine, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get(newRouter(false), "/panic")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	problem := decodeProblem(t, w)
+	assert.Equal(t, domain.CodeInternal, problem.Code)
+	assert.Equal(t, "nil map write in bar cache", problem.Detail)
+	assert.Contains(t, buf.String(), "panic recovered")
+	assert.Contains(t, buf.String(), "errors_test.go")
+
+	// Production hides what went wrong from the client but still logs it
+	buf.Reset()
+	w = get(newRouter(true), "/fail")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+	assert.Contains(t, buf.String(), "connection refused")
+	assert.NotContains(t, get(newRouter(true), "/panic").Body.String(), "nil map")
+
+	// Errors recorded next to a response are only logged
+	buf.Reset()
+	w = get(newRouter(true), "/written")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, buf.String(), "failed to send email")
+}
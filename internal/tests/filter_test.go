@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// filterCases run against every backend; each lists the bars it must return
+var filterCases = []struct {
+	name   string
+	filter db.Filter
+	want   []string
+}{
+	{name: "nil filter matches everything", filter: nil, want: []string{"1", "2", "3"}},
+	{name: "single field", filter: db.Filter{db.Eq("owner_id", "alice")}, want: []string{"1", "2"}},
+	{name: "all conditions must match", filter: db.Filter{db.Eq("owner_id", "alice"), db.Eq("status", "inactive")}, want: []string{"2"}},
+	{name: "id", filter: db.Filter{db.Eq("id", "3")}, want: []string{"3"}},
+	{name: "contradicting conditions", filter: db.Filter{db.Eq("status", "active"), db.Eq("status", "inactive")}, want: []string{}},
+	{name: "no match", filter: db.Filter{db.Eq("owner_id", "carol")}, want: []string{}},
+	{name: "in", filter: db.Filter{{Field: "owner_id", Op: db.OpIn, Value: []string{"bob", "carol"}}}, want: []string{"3"}},
+	{name: "not equal", filter: db.Filter{{Field: "status", Op: db.OpNe, Value: "active"}}, want: []string{"2"}},
+	{name: "contains ignores case", filter: db.Filter{{Field: "name", Op: db.OpContains, Value: "b"}}, want: []string{"2"}},
+	{name: "like", filter: db.Filter{db.Like("status", "in%")}, want: []string{"2"}},
+	{name: "like is anchored", filter: db.Filter{db.Like("status", "ctive")}, want: []string{}},
+	{name: "or", filter: db.Filter{db.Or(db.Eq("owner_id", "bob"), db.Eq("status", "inactive"))}, want: []string{"2", "3"}},
+	{name: "not", filter: db.Filter{db.Not(db.Eq("owner_id", "alice"))}, want: []string{"3"}},
+	{name: "between", filter: db.Filter{db.Between("id", "2", "3")}, want: []string{"2", "3"}},
+	{name: "nested", filter: db.Filter{db.Or(db.And(db.Eq("owner_id", "alice"), db.Ne("status", "active")), db.Not(db.In("id", "1", "2")))}, want: []string{"2", "3"}},
+}
+
+var filterFixtures = []*domain.Bar{
+	{ID: "1", Name: "A", Status: "active", OwnerID: "alice"},
+	{ID: "2", Name: "B", Status: "inactive", OwnerID: "alice"},
+	{ID: "3", Name: "C", Status: "active", OwnerID: "bob"},
+}
+
+func TestFilter_SQL(t *testing.T) {
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	tx, err := db.Filter{db.Eq("owner_id", "alice"), db.Eq("status", "active")}.Apply(gormDB, &[]domain.Bar{})
+	require.NoError(t, err)
+	stmt := tx.Find(&[]domain.Bar{}).Statement
+	assert.Equal(t, `SELECT * FROM "bars" WHERE "owner_id" = $1 AND "status" = $2`, stmt.SQL.String())
+	assert.Equal(t, []any{"alice", "active"}, stmt.Vars)
+
+	tx, err = db.Filter(nil).Apply(gormDB.Session(&gorm.Session{}), &[]domain.Bar{})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "bars"`, tx.Find(&[]domain.Bar{}).Statement.SQL.String())
+
+	// Fields are named by their JSON names and stored under their columns
+	tx, err = db.Filter{db.Eq("id", "1")}.Apply(gormDB.Session(&gorm.Session{}), &[]domain.Foo{})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "foos" WHERE "user_id" = $1`, tx.Find(&[]domain.Foo{}).Statement.SQL.String())
+
+	_, err = db.Filter{db.Eq("password", "x")}.Apply(gormDB.Session(&gorm.Session{}), &[]domain.Bar{})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestFilter_Operators(t *testing.T) {
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	filter := db.Filter{
+		{Field: "owner_id", Op: db.OpIn, Value: []string{"alice", "bob"}},
+		{Field: "name", Op: db.OpContains, Value: "50%"},
+		{Field: "status", Op: db.OpNe, Value: "inactive"},
+	}
+	tx, err := filter.Apply(gormDB, &[]domain.Bar{})
+	require.NoError(t, err)
+	stmt := tx.Find(&[]domain.Bar{}).Statement
+	assert.Equal(t, `SELECT * FROM "bars" WHERE "owner_id" IN ($1,$2) AND "name" ILIKE $3 AND "status" <> $4`, stmt.SQL.String())
+	assert.Equal(t, []any{"alice", "bob", `%50\%%`, "inactive"}, stmt.Vars)
+
+	doc, err := filter.BSON(&domain.Bar{})
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{
+		{Key: "owner_id", Value: bson.D{{Key: "$in", Value: []any{"alice", "bob"}}}},
+		{Key: "name;type:string", Value: primitive.Regex{Pattern: "50%", Options: "i"}},
+		{Key: "status;type:string", Value: bson.D{{Key: "$ne", Value: "inactive"}}},
+	}, doc)
+}
+
+func TestFilter_BSON(t *testing.T) {
+	doc, err := db.Filter(nil).BSON(&domain.Bar{})
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{}, doc)
+
+	doc, err = db.Filter{db.Eq("owner_id", "alice"), db.Eq("id", "3")}.BSON(&domain.Bar{})
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{{Key: "owner_id", Value: "alice"}, {Key: "_id", Value: "3"}}, doc)
+
+	// Keys follow the bson tags the driver stores the fields under
+	doc, err = db.Filter{db.Eq("status", "active"), db.Eq("status", "inactive")}.BSON(&domain.Bar{})
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "status;type:string", Value: "active"}},
+		bson.D{{Key: "status;type:string", Value: "inactive"}},
+	}}}, doc)
+}
+
+// parityBackend is a real database with its unit of work
+type parityBackend struct {
+	repo       db.BaseRepository
+	unitOfWork port.UnitOfWork
+	// transactional is false for a standalone MongoDB server
+	transactional bool
+}
+
+// parityBackends open the real databases parity tests run against. They need
+// TEST_POSTGRES_DSN and TEST_MONGO_URI; backends without one are skipped.
+var parityBackends = map[string]func(t *testing.T) parityBackend{
+	"postgresql": func(t *testing.T) parityBackend {
+		dsn := os.Getenv("TEST_POSTGRES_DSN")
+		if dsn == "" {
+			t.Skip("TEST_POSTGRES_DSN is not set")
+		}
+		gormDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		require.NoError(t, err)
+		require.NoError(t, gormDB.Migrator().DropTable(&domain.Bar{}, &domain.User{}))
+		require.NoError(t, gormDB.AutoMigrate(&domain.Bar{}, &domain.User{}))
+		t.Cleanup(func() { _ = gormDB.Migrator().DropTable(&domain.Bar{}, &domain.User{}) })
+		return parityBackend{repo: db.NewPgsqlRepository(gormDB), unitOfWork: db.NewPgsqlUnitOfWork(gormDB), transactional: true}
+	},
+	"mongodb": func(t *testing.T) parityBackend {
+		uri := os.Getenv("TEST_MONGO_URI")
+		if uri == "" {
+			t.Skip("TEST_MONGO_URI is not set")
+		}
+		client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
+		require.NoError(t, err)
+		database := client.Database("filter_parity_test")
+		require.NoError(t, database.Drop(context.Background()))
+		// Transactions cannot create collections before MongoDB 4.4
+		require.NoError(t, database.CreateCollection(context.Background(), "bar"))
+		require.NoError(t, db.EnsureMongoIndexes(context.Background(), database))
+		t.Cleanup(func() {
+			_ = database.Drop(context.Background())
+			_ = client.Disconnect(context.Background())
+		})
+		var hello bson.M
+		require.NoError(t, client.Database("admin").RunCommand(context.Background(), bson.D{{Key: "hello", Value: 1}}).Decode(&hello))
+		transactional := hello["setName"] != nil || hello["msg"] == "isdbgrid"
+		if _, err := db.NewMongoUnitOfWork(client, false); transactional {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err, "a standalone server needs allow_non_transactional")
+		}
+		unitOfWork, err := db.NewMongoUnitOfWork(client, true)
+		require.NoError(t, err)
+		return parityBackend{
+			repo:          db.NewMongoRepository(client, database.Name()),
+			unitOfWork:    unitOfWork,
+			transactional: transactional,
+		}
+	},
+}
+
+// TestFilter_BackendParity runs the filter cases against every backend
+func TestFilter_BackendParity(t *testing.T) {
+	for name, open := range parityBackends {
+		t.Run(name, func(t *testing.T) {
+			repo := open(t).repo
+			ctx := context.Background()
+			for _, bar := range filterFixtures {
+				copied := *bar
+				require.NoError(t, repo.Create(ctx, "bar", &copied))
+			}
+
+			for _, tc := range filterCases {
+				t.Run(tc.name, func(t *testing.T) {
+					var bars []*domain.Bar
+					require.NoError(t, repo.GetAll(ctx, "bar", &bars, tc.filter))
+					ids := make([]string, 0, len(bars))
+					for _, bar := range bars {
+						ids = append(ids, bar.ID)
+					}
+					sort.Strings(ids)
+					assert.Equal(t, tc.want, ids)
+				})
+			}
+		})
+	}
+}
+
+// TestRepository_DuplicateKeyParity checks that every backend enforces the
+// unique keys of entities and reports violations as conflicts
+func TestRepository_DuplicateKeyParity(t *testing.T) {
+	for name, open := range parityBackends {
+		t.Run(name, func(t *testing.T) {
+			repo := open(t).repo
+			ctx := context.Background()
+			require.NoError(t, repo.Create(ctx, "user", &domain.User{ID: "1", Email: "taken@example.com"}))
+			err := repo.Create(ctx, "user", &domain.User{ID: "2", Email: "taken@example.com"})
+			assert.ErrorIs(t, err, domain.ErrConflict)
+		})
+	}
+}
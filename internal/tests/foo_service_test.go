@@ -2,7 +2,6 @@ package tests
 
 import (
 	"context"
-	"errors"
 	"testing"
 
 	"go-gin-boilerplate/internal/domain"
@@ -24,6 +23,10 @@ func (m *MockFooRepo) GetAll(ctx context.Context) ([]*domain.Foo, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*domain.Foo), args.Error(1)
 }
+func (m *MockFooRepo) List(ctx context.Context, query domain.ListQuery) (domain.Page[*domain.Foo], error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).(domain.Page[*domain.Foo]), args.Error(1)
+}
 func (m *MockFooRepo) GetByID(ctx context.Context, id string) (*domain.Foo, error) {
 	args := m.Called(ctx, id)
 	// Handle nil pointer case properly
@@ -55,7 +58,7 @@ func (m *MockFooRepo) DeleteById(ctx context.Context, id string) error {
 
 func TestFooService_Create(t *testing.T) {
 	repo := new(MockFooRepo)
-	svc := service.NewFooService(repo)
+	svc := service.NewFooService(repo, service.NewPolicyAuthorizer(false))
 	ctx := context.Background()
 	foo := &domain.Foo{ID: "1", Name: "Test Foo"}
 
@@ -72,7 +75,7 @@ func TestFooService_Create(t *testing.T) {
 
 func TestFooService_GetAll(t *testing.T) {
 	repo := new(MockFooRepo)
-	svc := service.NewFooService(repo)
+	svc := service.NewFooService(repo, service.NewPolicyAuthorizer(false))
 	ctx := context.Background()
 	foos := []*domain.Foo{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}}
 
@@ -84,7 +87,7 @@ func TestFooService_GetAll(t *testing.T) {
 
 func TestFooService_GetByID(t *testing.T) {
 	repo := new(MockFooRepo)
-	svc := service.NewFooService(repo)
+	svc := service.NewFooService(repo, service.NewPolicyAuthorizer(false))
 	ctx := context.Background()
 	foo := &domain.Foo{ID: "1", Name: "A"}
 
@@ -100,7 +103,7 @@ func TestFooService_GetByID(t *testing.T) {
 
 func TestFooService_GetByName(t *testing.T) {
 	repo := new(MockFooRepo)
-	svc := service.NewFooService(repo)
+	svc := service.NewFooService(repo, service.NewPolicyAuthorizer(false))
 	ctx := context.Background()
 	foo := &domain.Foo{ID: "1", Name: "A"}
 
@@ -116,7 +119,7 @@ func TestFooService_GetByName(t *testing.T) {
 
 func TestFooService_UpdateById(t *testing.T) {
 	repo := new(MockFooRepo)
-	svc := service.NewFooService(repo)
+	svc := service.NewFooService(repo, service.NewPolicyAuthorizer(false))
 	ctx := context.Background()
 	foo := &domain.Foo{ID: "1", Name: "A"}
 	update := map[string]any{"name": "B"}
@@ -128,7 +131,7 @@ func TestFooService_UpdateById(t *testing.T) {
 	assert.Equal(t, "B", result.Name)
 
 	// Fixed: Use (*domain.Foo)(nil) instead of nil for proper type handling
-	repo.On("GetByID", ctx, "2").Return((*domain.Foo)(nil), errors.New("not found"))
+	repo.On("GetByID", ctx, "2").Return((*domain.Foo)(nil), domain.NewNotFoundError("not found"))
 	result, err = svc.UpdateById(ctx, "2", update)
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -140,7 +143,7 @@ func TestFooService_UpdateById(t *testing.T) {
 
 func TestFooService_DeleteById(t *testing.T) {
 	repo := new(MockFooRepo)
-	svc := service.NewFooService(repo)
+	svc := service.NewFooService(repo, service.NewPolicyAuthorizer(false))
 	ctx := context.Background()
 	foo := &domain.Foo{ID: "1", Name: "A"}
 
@@ -149,7 +152,7 @@ func TestFooService_DeleteById(t *testing.T) {
 	assert.NoError(t, svc.DeleteById(ctx, "1"))
 
 	// Fixed: Use (*domain.Foo)(nil) instead of nil for proper type handling
-	repo.On("GetByID", ctx, "2").Return((*domain.Foo)(nil), errors.New("not found"))
+	repo.On("GetByID", ctx, "2").Return((*domain.Foo)(nil), domain.NewNotFoundError("not found"))
 	assert.Error(t, svc.DeleteById(ctx, "2"))
 
 	assert.Error(t, svc.DeleteById(ctx, ""))
@@ -158,7 +161,7 @@ func TestFooService_DeleteById(t *testing.T) {
 // Example filter test for BaseRepository-like behavior
 func TestFooService_Filter(t *testing.T) {
 	repo := new(MockFooRepo)
-	svc := service.NewFooService(repo)
+	svc := service.NewFooService(repo, service.NewPolicyAuthorizer(false))
 	ctx := context.Background()
 	foos := []*domain.Foo{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}}
 
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/middleware"
+	"go-gin-boilerplate/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_Impersonate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", Roles: []string{domain.RoleAdmin}},
+		"2": {ID: "2", Email: "customer@example.com", Roles: []string{domain.RoleUser}},
+		"3": {ID: "3", Email: "other-admin@example.com", Roles: []string{domain.RoleAdmin}},
+	}}
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	admin := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleAdmin}})
+
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	resp, err := authSvc.Impersonate(admin, "2")
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), `msg="impersonation token issued" actor=1 subject=2`)
+	assert.Equal(t, "2", resp.UserID)
+	assert.Equal(t, "1", resp.ActorID)
+	assert.InDelta(t, 900, resp.ExpiresIn, 1)
+
+	claims, err := jwt.ValidateAccessToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "2", claims.Subject)
+	require.NotNil(t, claims.Actor)
+	assert.Equal(t, "1", claims.Actor.Subject)
+
+	_, err = authSvc.Impersonate(admin, "1")
+	assert.EqualError(t, err, "cannot impersonate yourself")
+	_, err = authSvc.Impersonate(admin, "3")
+	assert.EqualError(t, err, "cannot impersonate an administrator")
+	_, err = authSvc.Impersonate(admin, "missing")
+	assert.EqualError(t, err, "user not found")
+
+	// Impersonation cannot be chained
+	impersonated := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "2", ActorID: "1"})
+	_, err = authSvc.Impersonate(impersonated, "1")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	// The middleware exposes both identities and audits the request
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(true))
+	router.GET("/protected", middleware.AuthMiddleware(jwt, revocationStore, nil), func(c *gin.Context) {
+		identity := domain.IdentityFromContext(c.Request.Context())
+		assert.True(t, identity.IsImpersonated())
+		assert.True(t, identity.IsDelegated())
+		c.String(http.StatusOK, c.GetString("userID")+" as "+c.GetString("actorID"))
+	})
+	router.GET("/panics", middleware.AuthMiddleware(jwt, revocationStore, nil), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := performAuthRequest(router, resp.AccessToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2 as 1", w.Body.String())
+	assert.Contains(t, logs.String(), `msg="impersonated request" actor=1 subject=2 method=GET path=/protected status=200`)
+
+	// Requests that panic are audited too
+	req := httptest.NewRequest(http.MethodGet, "/panics", nil)
+	req.Header.Set("Authorization", "Bearer "+resp.AccessToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, logs.String(), `msg="impersonated request" actor=1 subject=2 method=GET path=/panics status=500`)
+}
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/config"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func privateKeyPEM(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestJWT_AsymmetricAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	cases := []struct {
+		alg string
+		key crypto.Signer
+		kty string
+	}{
+		{"RS256", rsaKey, "RSA"},
+		{"ES256", ecKey, "EC"},
+		{"EdDSA", edKey, "OKP"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.alg, func(t *testing.T) {
+			jwt, err := utils.NewJWT(&config.JWTConfig{
+				Algorithm:       tc.alg,
+				PrivateKey:      privateKeyPEM(t, tc.key),
+				AccessDuration:  time.Minute,
+				RefreshDuration: time.Hour,
+			})
+			require.NoError(t, err)
+
+			token, err := jwt.GenerateAccessToken("1", nil)
+			require.NoError(t, err)
+
+			claims, err := jwt.ValidateAccessToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "1", claims.Subject)
+
+			jwks := jwt.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, tc.kty, jwks.Keys[0].Kty)
+			assert.Equal(t, tc.alg, jwks.Keys[0].Alg)
+		})
+	}
+}
+
+func TestJWT_HMACHasNoPublicKeys(t *testing.T) {
+	assert.Empty(t, newTestJWT().JWKS().Keys)
+
+	_, err := utils.NewJWT(&config.JWTConfig{Algorithm: "ES256", PrivateKey: "not a key"})
+	assert.Error(t, err)
+}
+
+func publicKeyPEM(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestJWT_KeyRotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cfg := &config.JWTConfig{
+		ActiveKid: "old",
+		Keys: []config.JWTKeyConfig{
+			{Kid: "old", Algorithm: "ES256", PrivateKey: privateKeyPEM(t, oldKey)},
+		},
+		AccessDuration:  time.Minute,
+		RefreshDuration: time.Hour,
+	}
+	jwt, err := utils.NewJWT(cfg)
+	require.NoError(t, err)
+
+	oldToken, err := jwt.GenerateAccessToken("1", nil)
+	require.NoError(t, err)
+
+	// Rotate: the new key signs, the old one is kept for verification only
+	cfg.ActiveKid = "new"
+	cfg.Keys = []config.JWTKeyConfig{
+		{Kid: "new", Algorithm: "ES256", PrivateKey: privateKeyPEM(t, newKey)},
+		{Kid: "old", Algorithm: "ES256", PublicKey: publicKeyPEM(t, oldKey)},
+	}
+	require.NoError(t, jwt.ReloadKeys(cfg))
+
+	_, err = jwt.ValidateAccessToken(oldToken)
+	assert.NoError(t, err)
+
+	newToken, err := jwt.GenerateAccessToken("1", nil)
+	require.NoError(t, err)
+	_, err = jwt.ValidateAccessToken(newToken)
+	assert.NoError(t, err)
+
+	jwks := jwt.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "new", jwks.Keys[0].Kid)
+	assert.Equal(t, "old", jwks.Keys[1].Kid)
+
+	// Once the old key is dropped its tokens are rejected
+	cfg.Keys = cfg.Keys[:1]
+	require.NoError(t, jwt.ReloadKeys(cfg))
+	_, err = jwt.ValidateAccessToken(oldToken)
+	assert.Error(t, err)
+
+	// A verification-only key cannot be made active
+	cfg.ActiveKid = "old"
+	cfg.Keys = []config.JWTKeyConfig{{Kid: "old", Algorithm: "ES256", PublicKey: publicKeyPEM(t, oldKey)}}
+	assert.Error(t, jwt.ReloadKeys(cfg))
+}
@@ -0,0 +1,239 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/handler"
+	"go-gin-boilerplate/internal/middleware"
+	"go-gin-boilerplate/internal/repository"
+	"go-gin-boilerplate/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// fakeListBaseRepo serves bars in the order given and records the queries
+// it is asked to find
+type fakeListBaseRepo struct {
+	db.BaseRepository
+	bars    []*domain.Bar
+	queries []db.Query
+}
+
+func (r *fakeListBaseRepo) Find(_ context.Context, _ string, result any, query db.Query) error {
+	r.queries = append(r.queries, query)
+	bars := r.bars
+	if query.Limit > 0 && len(bars) > query.Limit {
+		bars = bars[:query.Limit]
+	}
+	*result.(*[]*domain.Bar) = bars
+	return nil
+}
+
+func (r *fakeListBaseRepo) Count(context.Context, string, any, db.Filter) (int64, error) {
+	return int64(len(r.bars)), nil
+}
+
+func TestQuery_Keyset(t *testing.T) {
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	query := db.Query{
+		Filter: db.Filter{db.Eq("status", "active")},
+		Sort:   []db.Sort{{Field: "name", Desc: true}, {Field: "id"}},
+		Limit:  3,
+		After:  []any{"B", "2"},
+	}
+
+	tx, err := query.Apply(gormDB, &[]domain.Bar{})
+	require.NoError(t, err)
+	stmt := tx.Find(&[]domain.Bar{}).Statement
+	assert.Equal(t, `SELECT * FROM "bars" WHERE "status" = $1 AND ("name" < $2 OR ("name" = $3 AND "id" > $4)) ORDER BY "name" DESC,"id" LIMIT $5`, stmt.SQL.String())
+	assert.Equal(t, []any{"active", "B", "B", "2", 3}, stmt.Vars)
+
+	doc, opts, err := query.BSON(&domain.Bar{})
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{
+		{Key: "status;type:string", Value: "active"},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "name;type:string", Value: bson.D{{Key: "$lt", Value: "B"}}}},
+			bson.D{{Key: "name;type:string", Value: "B"}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: "2"}}}},
+		}},
+	}, doc)
+	assert.Equal(t, bson.D{{Key: "name;type:string", Value: -1}, {Key: "_id", Value: 1}}, opts.Sort)
+	assert.EqualValues(t, 3, *opts.Limit)
+
+	_, err = db.Query{Sort: []db.Sort{{Field: "id"}}, After: []any{"1", "2"}}.Apply(gormDB.Session(&gorm.Session{}), &[]domain.Bar{})
+	assert.Error(t, err)
+}
+
+func TestBarRepository_ListCursor(t *testing.T) {
+	base := &fakeListBaseRepo{bars: filterFixtures}
+	repo := repository.NewBarRepository(base, "bar")
+	ctx := context.Background()
+
+	page, err := repo.List(ctx, domain.ListQuery{Sort: []domain.SortField{{Field: "name", Desc: true}}, Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.EqualValues(t, 3, page.Total)
+	require.NotEmpty(t, page.NextCursor)
+	// The order always ends with id, and one extra item is fetched to find a next page
+	assert.Equal(t, []db.Sort{{Field: "name", Desc: true}, {Field: "id"}}, base.queries[0].Sort)
+	assert.Equal(t, 3, base.queries[0].Limit)
+
+	_, err = repo.List(ctx, domain.ListQuery{Sort: []domain.SortField{{Field: "name", Desc: true}}, Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"B", "2"}, base.queries[1].After)
+
+	// A cursor is only valid for the sort order it was issued for
+	_, err = repo.List(ctx, domain.ListQuery{Limit: 2, Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	page, err = repo.List(ctx, domain.ListQuery{Limit: 3})
+	require.NoError(t, err)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestBarHandler_ListQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockBarRepo)
+	barHandler := handler.NewBarHandler(service.NewBarService(repo, newDefaultAuthorizer()))
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(false))
+	router.GET("/bar", func(c *gin.Context) {
+		identity := &domain.Identity{UserID: "owner", Roles: []string{domain.RoleUser}}
+		c.Request = c.Request.WithContext(domain.WithIdentity(c.Request.Context(), identity))
+	}, barHandler.GetAll)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bar?"+query, nil))
+		return w
+	}
+
+	repo.On("List", mock.Anything, domain.ListQuery{
+		Filters: []domain.FieldFilter{
+			{Field: "name", Operator: domain.FilterContains, Value: "bar"},
+			{Field: "owner_id", Operator: domain.FilterIn, Value: []string{"a", "b"}},
+			{Field: "status", Operator: domain.FilterEq, Value: "active"},
+		},
+		Sort:  []domain.SortField{{Field: "status"}, {Field: "name", Desc: true}},
+		Limit: domain.DefaultListLimit,
+	}).Return(domain.Page[*domain.Bar]{Items: []*domain.Bar{{ID: "1"}}, Total: 1}, nil)
+
+	w := get("status=active&name__contains=bar&owner_id__in=a,b&sort=status,-name")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"Bars retrieved successfully","data":{"items":[{"id":"1","name":"","description":"","status":"","owner_id":""}],"total":1}}`, w.Body.String())
+
+	w = get("limit=500&password=x&name__like=x&sort=secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	problem := decodeProblem(t, w)
+	assert.Equal(t, domain.CodeValidation, problem.Code)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "limit", Message: "must be between 1 and 100"},
+		{Field: "name__like", Message: "has an unknown operator"},
+	}, problem.Errors)
+
+	w = get("password=x&sort=secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "password", Message: "cannot be filtered by"},
+		{Field: "sort", Message: "cannot sort by secret"},
+	}, decodeProblem(t, w).Errors)
+
+	repo.AssertNumberOfCalls(t, "List", 1)
+}
+
+// TestList_BackendParity pages through the bars of every backend with a cursor
+func TestList_BackendParity(t *testing.T) {
+	for name, open := range parityBackends {
+		t.Run(name, func(t *testing.T) {
+			base := open(t).repo
+			ctx := context.Background()
+			for _, bar := range filterFixtures {
+				copied := *bar
+				require.NoError(t, base.Create(ctx, "bar", &copied))
+			}
+			repo := repository.NewBarRepository(base, "bar")
+
+			query := domain.ListQuery{Sort: []domain.SortField{{Field: "status"}, {Field: "name", Desc: true}}, Limit: 2}
+			var ids []string
+			for {
+				page, err := repo.List(ctx, query)
+				require.NoError(t, err)
+				assert.EqualValues(t, 3, page.Total)
+				for _, bar := range page.Items {
+					ids = append(ids, bar.ID)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				query.Cursor = page.NextCursor
+			}
+			assert.Equal(t, []string{"3", "1", "2"}, ids)
+
+			page, err := repo.List(ctx, domain.ListQuery{
+				Filters: []domain.FieldFilter{{Field: "owner_id", Operator: domain.FilterEq, Value: "alice"}},
+				Limit:   1,
+				Offset:  1,
+			})
+			require.NoError(t, err)
+			assert.EqualValues(t, 2, page.Total)
+			require.Len(t, page.Items, 1)
+			assert.Equal(t, "2", page.Items[0].ID)
+		})
+	}
+}
+
+func TestQuery_Composed(t *testing.T) {
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	query := db.Query{
+		Filter: db.Filter{
+			db.Or(db.Like("name", `A\_%`), db.In("owner_id", "alice", "bob")),
+			db.Not(db.Eq("status", "inactive")),
+			db.Between("id", "1", "3"),
+		},
+		Sort:   []db.Sort{{Field: "name"}},
+		Limit:  10,
+		Select: []string{"id", "name"},
+	}
+
+	tx, err := query.Apply(gormDB, &[]domain.Bar{})
+	require.NoError(t, err)
+	stmt := tx.Find(&[]domain.Bar{}).Statement
+	assert.Equal(t, `SELECT "id","name" FROM "bars" WHERE ("name" LIKE $1 OR "owner_id" IN ($2,$3)) AND "status" <> $4 AND ("id" >= $5 AND "id" <= $6) ORDER BY "name" LIMIT $7`, stmt.SQL.String())
+	assert.Equal(t, []any{`A\_%`, "alice", "bob", "inactive", "1", "3", 10}, stmt.Vars)
+
+	doc, opts, err := query.BSON(&domain.Bar{})
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "name;type:string", Value: primitive.Regex{Pattern: `^A_[\s\S]*$`}}},
+			bson.D{{Key: "owner_id", Value: bson.D{{Key: "$in", Value: []any{"alice", "bob"}}}}},
+		}},
+		{Key: "$nor", Value: bson.A{bson.D{{Key: "status;type:string", Value: "inactive"}}}},
+		{Key: "$and", Value: bson.A{
+			bson.D{{Key: "_id", Value: bson.D{{Key: "$gte", Value: "1"}}}},
+			bson.D{{Key: "_id", Value: bson.D{{Key: "$lte", Value: "3"}}}},
+		}},
+	}, doc)
+	assert.Equal(t, bson.D{{Key: "_id", Value: 1}, {Key: "name;type:string", Value: 1}}, opts.Projection)
+
+	// Empty groups mean different things to SQL and MongoDB, so they are rejected
+	_, err = db.Filter{db.Or()}.BSON(&domain.Bar{})
+	assert.Error(t, err)
+	_, err = db.Query{Select: []string{"password"}}.Apply(gormDB.Session(&gorm.Session{}), &[]domain.Bar{})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
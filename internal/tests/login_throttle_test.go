@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/handler"
+	"go-gin-boilerplate/internal/middleware"
+	"go-gin-boilerplate/internal/service"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginThrottler_ProgressiveDelayAndLockout(t *testing.T) {
+	throttler := service.NewLoginThrottler(cache.NewMemoryLoginAttemptStore(), domain.LoginThrottlePolicy{
+		MaxAttempts:     3,
+		IPMaxAttempts:   100,
+		Window:          time.Minute,
+		LockoutDuration: time.Hour,
+		BaseDelay:       10 * time.Millisecond,
+		MaxDelay:        15 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	require.NoError(t, throttler.Attempt(ctx, "admin@example.com", "10.0.0.1"))
+
+	var throttled *domain.LoginThrottledError
+	err := throttler.Attempt(ctx, "Admin@Example.com", "10.0.0.2")
+	require.True(t, errors.As(err, &throttled))
+	assert.Equal(t, 10*time.Millisecond, throttled.RetryAfter)
+
+	// The delay doubles but is capped
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, throttler.Attempt(ctx, "admin@example.com", "10.0.0.1"))
+	require.True(t, errors.As(throttler.Attempt(ctx, "admin@example.com", "10.0.0.1"), &throttled))
+	assert.Equal(t, 15*time.Millisecond, throttled.RetryAfter)
+
+	// Other accounts are not affected
+	assert.NoError(t, throttler.Attempt(ctx, "user@example.com", "10.0.0.1"))
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, throttler.Attempt(ctx, "admin@example.com", "10.0.0.1"))
+	time.Sleep(20 * time.Millisecond)
+	require.True(t, errors.As(throttler.Attempt(ctx, "admin@example.com", "10.0.0.1"), &throttled))
+	assert.Equal(t, time.Hour, throttled.RetryAfter)
+	require.True(t, errors.As(throttler.Attempt(ctx, "admin@example.com", "10.0.0.1"), &throttled))
+	assert.InDelta(t, time.Hour, throttled.RetryAfter, float64(time.Second))
+
+	// A successful login clears the account
+	require.NoError(t, throttler.RecordSuccess(ctx, "admin@example.com", "10.0.0.1"))
+	assert.NoError(t, throttler.Attempt(ctx, "admin@example.com", "10.0.0.1"))
+}
+
+func TestLoginThrottler_ParallelAttemptsAreCounted(t *testing.T) {
+	throttler := service.NewLoginThrottler(cache.NewMemoryLoginAttemptStore(), domain.LoginThrottlePolicy{
+		MaxAttempts:     3,
+		IPMaxAttempts:   100,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+	})
+
+	// Guesses sent at once cannot all get past the check before any is counted
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if throttler.Attempt(context.Background(), "admin@example.com", "10.0.0.1") == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(3), allowed.Load())
+}
+
+func TestLoginThrottler_LocksClientIP(t *testing.T) {
+	throttler := service.NewLoginThrottler(cache.NewMemoryLoginAttemptStore(), domain.LoginThrottlePolicy{
+		MaxAttempts:     100,
+		IPMaxAttempts:   2,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+	})
+	ctx := context.Background()
+
+	require.NoError(t, throttler.Attempt(ctx, "a@example.com", "10.0.0.1"))
+	require.NoError(t, throttler.Attempt(ctx, "b@example.com", "10.0.0.1"))
+
+	var throttled *domain.LoginThrottledError
+	assert.True(t, errors.As(throttler.Attempt(ctx, "c@example.com", "10.0.0.1"), &throttled))
+	assert.NoError(t, throttler.Attempt(ctx, "c@example.com", "10.0.0.2"))
+}
+
+func TestAuthHandler_LoginReturnsRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", PasswordHash: hash},
+	}}
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	accountSvc := service.NewAccountService(jwt, users, newFakeAPIKeyRepo(), revocationStore, &fakeMailer{}, "http://app.test")
+	throttler := service.NewLoginThrottler(cache.NewMemoryLoginAttemptStore(), domain.LoginThrottlePolicy{
+		MaxAttempts:     2,
+		IPMaxAttempts:   100,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+	})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(false))
+	router.POST("/login", handler.NewAuthHandler(authSvc, accountSvc, throttler).Login)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		body := `{"email":"admin@example.com","password":"` + password + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+
+	// Locked accounts are refused even with the right password
+	w := login("password123")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, domain.CodeTooManyRequests, decodeProblem(t, w).Code)
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 60, retryAfter, 1)
+}
+
+func TestAuthHandler_MFALoginDoesNotResetFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", PasswordHash: hash, MFAEnabled: true, MFASecret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"},
+	}}
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	accountSvc := service.NewAccountService(jwt, users, newFakeAPIKeyRepo(), revocationStore, &fakeMailer{}, "http://app.test")
+	throttler := service.NewLoginThrottler(cache.NewMemoryLoginAttemptStore(), domain.LoginThrottlePolicy{
+		MaxAttempts:     2,
+		IPMaxAttempts:   100,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+	})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(false))
+	router.POST("/login", handler.NewAuthHandler(authSvc, accountSvc, throttler).Login)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		body := `{"email":"admin@example.com","password":"` + password + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+	// The right password only yields an MFA challenge, which is not a success
+	assert.Equal(t, http.StatusOK, login("password123").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+	assert.Equal(t, http.StatusTooManyRequests, login("password123").Code)
+}
@@ -0,0 +1,337 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/service"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserRepo keeps users in memory and applies the account and MFA updates.
+// It is safe for concurrent use.
+type fakeUserRepo struct {
+	mu    sync.Mutex
+	users map[string]*domain.User
+}
+
+func (r *fakeUserRepo) Create(_ context.Context, user *domain.User) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = user
+	return user, nil
+}
+func (r *fakeUserRepo) GetByID(_ context.Context, id string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.get(id)
+}
+func (r *fakeUserRepo) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			return r.get(user.ID)
+		}
+	}
+	return nil, domain.NewNotFoundError("entity not found")
+}
+func (r *fakeUserRepo) get(id string) (*domain.User, error) {
+	if user, ok := r.users[id]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, domain.NewNotFoundError("entity not found")
+}
+func (r *fakeUserRepo) UpdateById(_ context.Context, id string, update map[string]any) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.NewNotFoundError("entity not found")
+	}
+	for key, value := range update {
+		switch key {
+		case "password_hash":
+			user.PasswordHash = value.(string)
+		case "email_verified":
+			user.EmailVerified = value.(bool)
+		case "mfa_enabled":
+			user.MFAEnabled = value.(bool)
+		case "mfa_secret":
+			user.MFASecret = value.(string)
+		case "mfa_recovery_codes":
+			user.MFARecoveryCodes = value.([]string)
+		case "mfa_last_step":
+			user.MFALastStep = value.(int64)
+		}
+	}
+	return r.get(id)
+}
+
+// newTestLoginThrottler locks out after ten failures without slowing down the
+// attempts before that
+func newTestLoginThrottler() port.LoginThrottler {
+	return service.NewLoginThrottler(cache.NewMemoryLoginAttemptStore(), domain.LoginThrottlePolicy{
+		MaxAttempts:     10,
+		IPMaxAttempts:   100,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+	})
+}
+
+func TestTOTPCode_RFC6238Vector(t *testing.T) {
+	// Secret "12345678901234567890" at T=59s (RFC 6238 appendix B, truncated to 6 digits)
+	code, err := utils.TOTPCode("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", utils.TOTPStep(time.Unix(59, 0)))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	_, ok := utils.ValidateTOTP("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "287082", time.Unix(59+30, 0))
+	assert.True(t, ok, "codes from the previous period are accepted")
+	_, ok = utils.ValidateTOTP("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "287082", time.Unix(59+90, 0))
+	assert.False(t, ok)
+}
+
+func TestMFAService_EnrollAndTwoStepLogin(t *testing.T) {
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", PasswordHash: hash},
+	}}
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	mfaSvc := service.NewMFAService(jwt, users, revocationStore, authSvc, newTestLoginThrottler(), "test")
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+	login := domain.LoginRequest{Email: "admin@example.com", Password: "password123"}
+
+	enrollment, err := mfaSvc.Enroll(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.OTPAuthURI, "otpauth://totp/test:admin@example.com?")
+	assert.Len(t, enrollment.RecoveryCodes, 10)
+
+	// Enrollment alone does not change the login
+	resp, err := authSvc.Login(ctx, login)
+	require.NoError(t, err)
+	assert.False(t, resp.MFARequired)
+
+	assert.EqualError(t, mfaSvc.Activate(ctx, "000000"), "invalid MFA code")
+	step := utils.TOTPStep(time.Now())
+	code, err := utils.TOTPCode(enrollment.Secret, step)
+	require.NoError(t, err)
+	require.NoError(t, mfaSvc.Activate(ctx, code))
+
+	resp, err = authSvc.Login(ctx, login)
+	require.NoError(t, err)
+	assert.True(t, resp.MFARequired)
+	assert.Empty(t, resp.AccessToken)
+
+	// The code used for activation cannot be replayed
+	_, err = mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: code})
+	assert.Error(t, err)
+
+	nextCode, err := utils.TOTPCode(enrollment.Secret, step+1)
+	require.NoError(t, err)
+	verified, err := mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: nextCode})
+	require.NoError(t, err)
+	_, err = jwt.ValidateAccessToken(verified.AccessToken)
+	assert.NoError(t, err)
+
+	// Challenge tokens are single use and are not access tokens
+	_, err = mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: enrollment.RecoveryCodes[0]})
+	assert.Error(t, err)
+	_, err = jwt.ValidateAccessToken(resp.MFAToken)
+	assert.Error(t, err)
+
+	// Recovery codes work once
+	resp, err = authSvc.Login(ctx, login)
+	require.NoError(t, err)
+	_, err = mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: enrollment.RecoveryCodes[0]})
+	require.NoError(t, err)
+	resp, err = authSvc.Login(ctx, login)
+	require.NoError(t, err)
+	_, err = mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: enrollment.RecoveryCodes[0]})
+	assert.Error(t, err)
+
+	require.NoError(t, mfaSvc.Disable(ctx, enrollment.RecoveryCodes[1]))
+	resp, err = authSvc.Login(ctx, login)
+	require.NoError(t, err)
+	assert.False(t, resp.MFARequired)
+	assert.NotEmpty(t, resp.AccessToken)
+}
+
+func TestMFAService_VerifyRevokesChallengeAfterFailures(t *testing.T) {
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", PasswordHash: hash, MFAEnabled: true, MFASecret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"},
+	}}
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	throttler := service.NewLoginThrottler(cache.NewMemoryLoginAttemptStore(), domain.LoginThrottlePolicy{
+		MaxAttempts:     4,
+		IPMaxAttempts:   100,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+	})
+	mfaSvc := service.NewMFAService(jwt, users, revocationStore, authSvc, throttler, "test")
+	ctx := domain.WithClientInfo(context.Background(), domain.ClientInfo{IP: "10.0.0.1"})
+	login := domain.LoginRequest{Email: "admin@example.com", Password: "password123"}
+
+	resp, err := authSvc.Login(ctx, login)
+	require.NoError(t, err)
+	for range 3 {
+		_, err = mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: "000000"})
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	}
+
+	// The third wrong code revoked the challenge, so the right code is refused
+	code, err := utils.TOTPCode("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", utils.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	_, err = mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: code})
+	assert.EqualError(t, err, "invalid MFA token")
+
+	// Wrong codes count as failed logins of the account
+	resp, err = authSvc.Login(ctx, login)
+	require.NoError(t, err)
+	_, err = mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: "000000"})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	var throttled *domain.LoginThrottledError
+	_, err = mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: code})
+	assert.ErrorAs(t, err, &throttled)
+}
+
+func TestMFAService_ConcurrentVerifyUsesCodeOnce(t *testing.T) {
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", PasswordHash: hash},
+	}}
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	mfaSvc := service.NewMFAService(jwt, users, revocationStore, authSvc, newTestLoginThrottler(), "test")
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+	login := domain.LoginRequest{Email: "admin@example.com", Password: "password123"}
+
+	enrollment, err := mfaSvc.Enroll(ctx)
+	require.NoError(t, err)
+	step := utils.TOTPStep(time.Now())
+	code, err := utils.TOTPCode(enrollment.Secret, step-1)
+	require.NoError(t, err)
+	require.NoError(t, mfaSvc.Activate(ctx, code))
+
+	// verifyConcurrently submits the code with several challenges at once and
+	// returns how many logins succeeded
+	verifyConcurrently := func(code string) int {
+		const attempts = 8
+		challenges := make([]string, attempts)
+		for i := range challenges {
+			resp, err := authSvc.Login(ctx, login)
+			require.NoError(t, err)
+			challenges[i] = resp.MFAToken
+		}
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		succeeded := 0
+		for _, challenge := range challenges {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := mfaSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: challenge, Code: code}); err == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		return succeeded
+	}
+
+	code, err = utils.TOTPCode(enrollment.Secret, step)
+	require.NoError(t, err)
+	assert.Equal(t, 1, verifyConcurrently(code))
+
+	assert.Equal(t, 1, verifyConcurrently(enrollment.RecoveryCodes[0]))
+	assert.Equal(t, 1, verifyConcurrently(enrollment.RecoveryCodes[1]))
+	// Both removals were kept
+	assert.Len(t, users.users["1"].MFARecoveryCodes, 8)
+
+}
+
+// barrierUserRepo holds the first n user lookups until all of them arrived,
+// so concurrent requests pass their checks before any of them goes on
+type barrierUserRepo struct {
+	*fakeUserRepo
+	mu      sync.Mutex
+	n       int
+	release chan struct{}
+}
+
+func (r *barrierUserRepo) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	r.mu.Lock()
+	if r.n > 0 {
+		r.n--
+		if r.n == 0 {
+			close(r.release)
+		}
+		r.mu.Unlock()
+		<-r.release
+	} else {
+		r.mu.Unlock()
+	}
+	return r.fakeUserRepo.GetByID(ctx, id)
+}
+
+func TestMFAService_ChallengeRaceKeepsRecoveryCodes(t *testing.T) {
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", PasswordHash: hash},
+	}}
+	jwt := newTestJWT()
+	revocationStore := cache.NewMemoryRevocationStore()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), revocationStore, cache.NewMemorySessionStore())
+	mfaSvc := service.NewMFAService(jwt, users, revocationStore, authSvc, newTestLoginThrottler(), "test")
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+
+	enrollment, err := mfaSvc.Enroll(ctx)
+	require.NoError(t, err)
+	code, err := utils.TOTPCode(enrollment.Secret, utils.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	require.NoError(t, mfaSvc.Activate(ctx, code))
+	resp, err := authSvc.Login(ctx, domain.LoginRequest{Email: "admin@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	// Different recovery codes race for one challenge
+	const attempts = 4
+	racing := &barrierUserRepo{fakeUserRepo: users, n: attempts, release: make(chan struct{})}
+	racingSvc := service.NewMFAService(jwt, racing, revocationStore, authSvc, newTestLoginThrottler(), "test")
+	var wg sync.WaitGroup
+	var succeeded atomic.Int32
+	for _, recoveryCode := range enrollment.RecoveryCodes[:attempts] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := racingSvc.Verify(ctx, domain.MFAVerifyRequest{MFAToken: resp.MFAToken, Code: recoveryCode}); err == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Only the winner's code is used up
+	assert.Equal(t, int32(1), succeeded.Load())
+	assert.Len(t, users.users["1"].MFARecoveryCodes, 9)
+}
@@ -0,0 +1,268 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/handler"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/service"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOAuthClientRepo keeps OAuth clients in memory
+type fakeOAuthClientRepo struct {
+	clients map[string]*domain.OAuthClient
+}
+
+func newFakeOAuthClientRepo() *fakeOAuthClientRepo {
+	return &fakeOAuthClientRepo{clients: make(map[string]*domain.OAuthClient)}
+}
+
+func (r *fakeOAuthClientRepo) Create(_ context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error) {
+	id, err := utils.RandomID(16)
+	if err != nil {
+		return nil, err
+	}
+	client.ID = id
+	r.clients[id] = client
+	return client, nil
+}
+func (r *fakeOAuthClientRepo) GetAll(_ context.Context) ([]*domain.OAuthClient, error) {
+	var clients []*domain.OAuthClient
+	for _, client := range r.clients {
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+func (r *fakeOAuthClientRepo) GetByID(_ context.Context, id string) (*domain.OAuthClient, error) {
+	if client, ok := r.clients[id]; ok {
+		return client, nil
+	}
+	return nil, domain.NewNotFoundError("entity not found")
+}
+func (r *fakeOAuthClientRepo) DeleteById(_ context.Context, id string) error {
+	delete(r.clients, id)
+	return nil
+}
+
+func newTestOAuthService(users port.UserRepository, revocationStore port.TokenRevocationStore) port.OAuthService {
+	return service.NewOAuthService(newTestJWT(), newFakeOAuthClientRepo(), cache.NewMemoryAuthorizationCodeStore(time.Minute), users, revocationStore)
+}
+
+var adminCtx = domain.WithIdentity(context.Background(), &domain.Identity{UserID: "admin", Roles: []string{domain.RoleAdmin}})
+
+func TestOAuthService_ClientCredentials(t *testing.T) {
+	revocationStore := cache.NewMemoryRevocationStore()
+	svc := newTestOAuthService(new(MockUserRepo), revocationStore)
+	ctx := context.Background()
+
+	registered, err := svc.RegisterClient(adminCtx, domain.RegisterOAuthClientRequest{
+		Name:       "billing-service",
+		GrantTypes: []string{domain.GrantTypeClientCredentials},
+		Scopes:     []string{"bar:read", "foo:write"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, registered.ClientSecret)
+	clientID := registered.Client.ID
+
+	token, err := svc.Token(ctx, domain.TokenRequest{
+		GrantType:    domain.GrantTypeClientCredentials,
+		ClientID:     clientID,
+		ClientSecret: registered.ClientSecret,
+		Scope:        "bar:read",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, "bar:read", token.Scope)
+	assert.Equal(t, int64(15*60), token.ExpiresIn)
+
+	// The client is limited to its granted scopes
+	identity := &domain.Identity{UserID: clientID, ClientID: clientID, Scopes: []string{"bar:read"}}
+	assert.True(t, identity.Can(domain.PermissionBarRead))
+	assert.False(t, identity.Can(domain.PermissionBarWrite))
+
+	introspection, err := svc.Introspect(ctx, clientID, registered.ClientSecret, token.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, clientID, introspection.ClientID)
+	assert.Equal(t, clientID, introspection.Sub)
+	assert.Equal(t, "bar:read", introspection.Scope)
+
+	require.NoError(t, revocationStore.Revoke(ctx, introspection.Jti, time.Unix(introspection.Exp, 0)))
+	introspection, err = svc.Introspect(ctx, clientID, registered.ClientSecret, token.AccessToken)
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+
+	introspection, err = svc.Introspect(ctx, clientID, registered.ClientSecret, "not-a-token")
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+
+	_, err = svc.Token(ctx, domain.TokenRequest{GrantType: domain.GrantTypeClientCredentials, ClientID: clientID, ClientSecret: "wrong"})
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidClient)
+	_, err = svc.Token(ctx, domain.TokenRequest{GrantType: domain.GrantTypeClientCredentials, ClientID: clientID, ClientSecret: registered.ClientSecret, Scope: "bar:delete"})
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidScope)
+	_, err = svc.Token(ctx, domain.TokenRequest{GrantType: "password", ClientID: clientID, ClientSecret: registered.ClientSecret})
+	assert.ErrorIs(t, err, domain.ErrOAuthUnsupportedGrantType)
+	_, err = svc.Introspect(ctx, clientID, "wrong", token.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidClient)
+
+	// Only users with the roles to back the scopes can register clients
+	userCtx := domain.WithIdentity(ctx, &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+	_, err = svc.RegisterClient(userCtx, domain.RegisterOAuthClientRequest{
+		Name:       "x",
+		GrantTypes: []string{domain.GrantTypeClientCredentials},
+		Scopes:     []string{"bar:delete"},
+	})
+	assert.Error(t, err)
+}
+
+func TestOAuthService_AuthorizationCodeWithPKCE(t *testing.T) {
+	users := new(MockUserRepo)
+	users.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: "1", Roles: []string{domain.RoleUser}}, nil)
+	svc := newTestOAuthService(users, cache.NewMemoryRevocationStore())
+	ctx := context.Background()
+	userCtx := domain.WithIdentity(ctx, &domain.Identity{UserID: "1", Roles: []string{domain.RoleUser}})
+
+	registered, err := svc.RegisterClient(adminCtx, domain.RegisterOAuthClientRequest{
+		Name:         "mobile-app",
+		Public:       true,
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		GrantTypes:   []string{domain.GrantTypeAuthorizationCode},
+		Scopes:       []string{"bar:read", "bar:delete"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, registered.ClientSecret)
+	clientID := registered.Client.ID
+
+	verifier := "a-sufficiently-long-random-code-verifier-value"
+	authorize := func() string {
+		redirect, err := svc.Authorize(userCtx, domain.AuthorizeRequest{
+			ResponseType:        "code",
+			ClientID:            clientID,
+			RedirectURI:         "https://app.example.com/callback",
+			State:               "xyz",
+			CodeChallenge:       utils.PKCEChallenge(verifier),
+			CodeChallengeMethod: "S256",
+		})
+		require.NoError(t, err)
+		parsed, err := url.Parse(redirect)
+		require.NoError(t, err)
+		assert.Equal(t, "xyz", parsed.Query().Get("state"))
+		return parsed.Query().Get("code")
+	}
+
+	// A public client must use PKCE and an exact registered redirect URI
+	_, err = svc.Authorize(userCtx, domain.AuthorizeRequest{ResponseType: "code", ClientID: clientID, RedirectURI: "https://app.example.com/callback"})
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidRequest)
+	_, err = svc.Authorize(userCtx, domain.AuthorizeRequest{
+		ResponseType: "code", ClientID: clientID, RedirectURI: "https://evil.example.com/callback",
+		CodeChallenge: utils.PKCEChallenge(verifier), CodeChallengeMethod: "S256",
+	})
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidRequest)
+
+	code := authorize()
+	_, err = svc.Token(ctx, domain.TokenRequest{
+		GrantType: domain.GrantTypeAuthorizationCode, ClientID: clientID, Code: code,
+		RedirectURI: "https://app.example.com/callback", CodeVerifier: "wrong-verifier",
+	})
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidGrant)
+
+	code = authorize()
+	request := domain.TokenRequest{
+		GrantType: domain.GrantTypeAuthorizationCode, ClientID: clientID, Code: code,
+		RedirectURI: "https://app.example.com/callback", CodeVerifier: verifier,
+	}
+	token, err := svc.Token(ctx, request)
+	require.NoError(t, err)
+	// bar:delete is registered for the client but the user's roles do not grant it
+	assert.Equal(t, "bar:read", token.Scope)
+
+	claims, err := newTestJWT().ValidateAccessToken(token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "1", claims.Subject)
+	assert.Equal(t, clientID, claims.ClientID)
+
+	// Codes are single use
+	_, err = svc.Token(ctx, request)
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidGrant)
+
+	// Public clients cannot introspect
+	_, err = svc.Introspect(ctx, clientID, "", token.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidClient)
+}
+
+func TestOAuthHandler_TokenEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := newTestOAuthService(new(MockUserRepo), cache.NewMemoryRevocationStore())
+	registered, err := svc.RegisterClient(adminCtx, domain.RegisterOAuthClientRequest{
+		Name:       "billing-service",
+		GrantTypes: []string{domain.GrantTypeClientCredentials},
+		Scopes:     []string{"bar:read"},
+	})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.POST("/oauth/token", handler.NewOAuthHandler(svc).Token)
+	request := func(secret string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader("grant_type=client_credentials"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(registered.Client.ID, secret)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(registered.ClientSecret)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	var token domain.TokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &token))
+	assert.NotEmpty(t, token.AccessToken)
+
+	w = request("wrong")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var oauthErr domain.OAuthErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &oauthErr))
+	assert.Equal(t, "invalid_client", oauthErr.Error)
+	assert.Equal(t, "client authentication failed", oauthErr.ErrorDescription)
+}
+
+func TestOAuthService_IntrospectRevokedSession(t *testing.T) {
+	revocationStore := cache.NewMemoryRevocationStore()
+	svc := newTestOAuthService(new(MockUserRepo), revocationStore)
+	ctx := context.Background()
+
+	registered, err := svc.RegisterClient(adminCtx, domain.RegisterOAuthClientRequest{
+		Name:       "gateway",
+		GrantTypes: []string{domain.GrantTypeClientCredentials},
+		Scopes:     []string{"bar:read"},
+	})
+	require.NoError(t, err)
+	clientID := registered.Client.ID
+
+	token, err := newTestJWT().GenerateSessionAccessToken("1", []string{domain.RoleUser}, "session-1")
+	require.NoError(t, err)
+	introspection, err := svc.Introspect(ctx, clientID, registered.ClientSecret, token)
+	require.NoError(t, err)
+	assert.True(t, introspection.Active)
+
+	// Ending the session deactivates its access tokens, as the middleware does
+	require.NoError(t, revocationStore.Revoke(ctx, "session-1", time.Now().Add(time.Hour)))
+	introspection, err = svc.Introspect(ctx, clientID, registered.ClientSecret, token)
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+}
@@ -0,0 +1,271 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/config"
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/service"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubOIDCProvider is a minimal OpenID Connect provider: it serves discovery
+// and JWKS documents and redeems the single code it handed out, checking PKCE
+type stubOIDCProvider struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	subject   string
+	email     string
+	challenge string
+	nonce     string
+}
+
+func newStubOIDCProvider(t *testing.T) *stubOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	stub := &stubOIDCProvider{key: key, subject: "ext-1", email: "ext@example.com"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(utils.OIDCMetadata{
+			Issuer:                stub.URL,
+			AuthorizationEndpoint: stub.URL + "/authorize",
+			TokenEndpoint:         stub.URL + "/token",
+			JWKSURI:               stub.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(utils.JSONWebKeySet{Keys: []utils.JSONWebKey{{
+			Kty: "RSA",
+			Kid: "stub",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("code") != "good-code" || utils.PKCEChallenge(r.PostFormValue("code_verifier")) != stub.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, &utils.IDTokenClaims{
+			Nonce:         stub.nonce,
+			Email:         stub.email,
+			EmailVerified: true,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    stub.URL,
+				Subject:   stub.subject,
+				Audience:  jwt.ClaimStrings{"client-id"},
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		})
+		token.Header["kid"] = "stub"
+		idToken, err := token.SignedString(key)
+		require.NoError(t, err)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+	})
+
+	stub.Server = httptest.NewServer(mux)
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+// authorize plays the user agent: it follows the authorization URL and
+// returns the state the provider would send back with the code
+func (s *stubOIDCProvider) authorize(t *testing.T, authURL string) string {
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, s.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	s.challenge = query.Get("code_challenge")
+	s.nonce = query.Get("nonce")
+	return query.Get("state")
+}
+
+// fakeExternalIdentityRepo keeps external identities in memory
+type fakeExternalIdentityRepo struct {
+	identities map[string]*domain.ExternalIdentity
+}
+
+func (r *fakeExternalIdentityRepo) Create(_ context.Context, identity *domain.ExternalIdentity) (*domain.ExternalIdentity, error) {
+	identity.ID = identity.Provider + ":" + identity.Subject
+	r.identities[identity.ID] = identity
+	return identity, nil
+}
+func (r *fakeExternalIdentityRepo) GetByProviderSubject(_ context.Context, provider, subject string) (*domain.ExternalIdentity, error) {
+	if identity, ok := r.identities[provider+":"+subject]; ok {
+		return identity, nil
+	}
+	return nil, domain.NewNotFoundError("entity not found")
+}
+
+// inTransactionKey marks the context of a fakeUnitOfWork
+type inTransactionKey struct{}
+
+// fakeUnitOfWork runs units of work without a database, marking their context
+// so tests can tell which repository calls a unit of work covered
+type fakeUnitOfWork struct{}
+
+func (fakeUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, inTransactionKey{}, true))
+}
+
+func inTransaction(ctx context.Context) bool {
+	return ctx.Value(inTransactionKey{}) != nil
+}
+
+func TestOIDCService_ProvisionAndLink(t *testing.T) {
+	stub := newStubOIDCProvider(t)
+	users := new(MockUserRepo)
+	jwt := newTestJWT()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), cache.NewMemoryRevocationStore(), cache.NewMemorySessionStore())
+	identities := &fakeExternalIdentityRepo{identities: make(map[string]*domain.ExternalIdentity)}
+	providers := map[string]*utils.OIDCProvider{
+		"stub": utils.NewOIDCProvider(config.OIDCProviderConfig{
+			Issuer:      stub.URL,
+			ClientID:    "client-id",
+			RedirectURL: "http://localhost/callback",
+		}),
+	}
+	svc := service.NewOIDCService(providers, cache.NewMemoryOIDCStateStore(time.Minute), identities, users, authSvc, fakeUnitOfWork{})
+	ctx := context.Background()
+
+	user := &domain.User{ID: "1", Email: "ext@example.com", Roles: []string{domain.RoleUser}}
+	users.On("GetByEmail", mock.Anything, "ext@example.com").Return(nil, domain.NewNotFoundError("entity not found")).Once()
+	// Provisioning and linking happen in one unit of work
+	users.On("Create", mock.MatchedBy(inTransaction), mock.MatchedBy(func(u *domain.User) bool {
+		return u.Email == "ext@example.com" && u.PasswordHash == ""
+	})).Return(user, nil).Once()
+	users.On("GetByID", mock.Anything, "1").Return(user, nil)
+
+	// First login provisions the user and links the external account
+	authURL, err := svc.AuthorizationURL(ctx, "stub")
+	require.NoError(t, err)
+	state := stub.authorize(t, authURL)
+
+	resp, err := svc.Callback(ctx, "stub", "good-code", state)
+	require.NoError(t, err)
+	claims, err := jwt.ValidateAccessToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "1", claims.Subject)
+	assert.Contains(t, identities.identities, "stub:ext-1")
+
+	// The state is single use
+	_, err = svc.Callback(ctx, "stub", "good-code", state)
+	assert.ErrorIs(t, err, domain.ErrOIDCStateInvalid)
+
+	// Second login finds the linked user without touching the email lookup
+	authURL, err = svc.AuthorizationURL(ctx, "stub")
+	require.NoError(t, err)
+	state = stub.authorize(t, authURL)
+	_, err = svc.Callback(ctx, "stub", "good-code", state)
+	require.NoError(t, err)
+	users.AssertNumberOfCalls(t, "Create", 1)
+
+	// The provider does not replace the user's second factor
+	user.MFAEnabled = true
+	authURL, err = svc.AuthorizationURL(ctx, "stub")
+	require.NoError(t, err)
+	state = stub.authorize(t, authURL)
+	resp, err = svc.Callback(ctx, "stub", "good-code", state)
+	require.NoError(t, err)
+	assert.True(t, resp.MFARequired)
+	assert.Empty(t, resp.AccessToken)
+	_, err = jwt.ValidateMFAToken(resp.MFAToken)
+	assert.NoError(t, err)
+
+	_, err = svc.AuthorizationURL(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrOIDCProviderNotFound)
+}
+
+func TestOIDCService_RejectsInvalidResponses(t *testing.T) {
+	stub := newStubOIDCProvider(t)
+	users := new(MockUserRepo)
+	authSvc := service.NewAuthService(newTestJWT(), users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), cache.NewMemoryRevocationStore(), cache.NewMemorySessionStore())
+	identities := &fakeExternalIdentityRepo{identities: make(map[string]*domain.ExternalIdentity)}
+	providers := map[string]*utils.OIDCProvider{
+		"stub": utils.NewOIDCProvider(config.OIDCProviderConfig{
+			Issuer:      stub.URL,
+			ClientID:    "client-id",
+			RedirectURL: "http://localhost/callback",
+		}),
+	}
+	svc := service.NewOIDCService(providers, cache.NewMemoryOIDCStateStore(time.Minute), identities, users, authSvc, fakeUnitOfWork{})
+	ctx := context.Background()
+
+	// A code issued for a different PKCE challenge is refused by the provider
+	authURL, err := svc.AuthorizationURL(ctx, "stub")
+	require.NoError(t, err)
+	state := stub.authorize(t, authURL)
+	stub.challenge = "someone-elses-challenge"
+	_, err = svc.Callback(ctx, "stub", "good-code", state)
+	assert.Error(t, err)
+
+	// An ID token carrying another login's nonce is rejected
+	authURL, err = svc.AuthorizationURL(ctx, "stub")
+	require.NoError(t, err)
+	state = stub.authorize(t, authURL)
+	stub.nonce = "replayed-nonce"
+	_, err = svc.Callback(ctx, "stub", "good-code", state)
+	assert.EqualError(t, err, "invalid id token")
+
+	_, err = svc.Callback(ctx, "stub", "good-code", "unknown-state")
+	assert.ErrorIs(t, err, domain.ErrOIDCStateInvalid)
+	users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestOIDCService_RefusesLinkToUnverifiedAccount(t *testing.T) {
+	stub := newStubOIDCProvider(t)
+	users := new(MockUserRepo)
+	authSvc := service.NewAuthService(newTestJWT(), users, newFakeAPIKeyRepo(), cache.NewMemoryRefreshTokenStore(), cache.NewMemoryRevocationStore(), cache.NewMemorySessionStore())
+	identities := &fakeExternalIdentityRepo{identities: make(map[string]*domain.ExternalIdentity)}
+	providers := map[string]*utils.OIDCProvider{
+		"stub": utils.NewOIDCProvider(config.OIDCProviderConfig{
+			Issuer:      stub.URL,
+			ClientID:    "client-id",
+			RedirectURL: "http://localhost/callback",
+		}),
+	}
+	svc := service.NewOIDCService(providers, cache.NewMemoryOIDCStateStore(time.Minute), identities, users, authSvc, fakeUnitOfWork{})
+	ctx := context.Background()
+
+	// Someone registered the address with a password but never confirmed it
+	squatter := &domain.User{ID: "1", Email: "ext@example.com", PasswordHash: "hash", Roles: []string{domain.RoleUser}}
+	users.On("GetByEmail", mock.Anything, "ext@example.com").Return(squatter, nil)
+
+	authURL, err := svc.AuthorizationURL(ctx, "stub")
+	require.NoError(t, err)
+	state := stub.authorize(t, authURL)
+	_, err = svc.Callback(ctx, "stub", "good-code", state)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Empty(t, identities.identities)
+
+	// Once the owner verifies the email, the accounts are linked
+	squatter.EmailVerified = true
+	users.On("GetByID", mock.Anything, "1").Return(squatter, nil)
+	authURL, err = svc.AuthorizationURL(ctx, "stub")
+	require.NoError(t, err)
+	state = stub.authorize(t, authURL)
+	_, err = svc.Callback(ctx, "stub", "good-code", state)
+	require.NoError(t, err)
+	assert.Contains(t, identities.identities, "stub:ext-1")
+	users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
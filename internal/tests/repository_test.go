@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"go-gin-boilerplate/internal/db"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/port"
+	"go-gin-boilerplate/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBarBaseRepo keeps bars in memory for the BaseRepository calls the
+// generic repository makes by ID, and records the models it passes
+type memoryBarBaseRepo struct {
+	db.BaseRepository
+	bars   map[string]domain.Bar
+	models []any
+}
+
+func (r *memoryBarBaseRepo) Create(_ context.Context, _ string, model any) error {
+	bar := model.(*domain.Bar)
+	r.bars[bar.ID] = *bar
+	return nil
+}
+
+func (r *memoryBarBaseRepo) GetById(_ context.Context, _ string, id string, result any) error {
+	bar, ok := r.bars[id]
+	if !ok {
+		return domain.NewNotFoundError("entity not found")
+	}
+	*result.(*domain.Bar) = bar
+	return nil
+}
+
+func (r *memoryBarBaseRepo) UpdateById(_ context.Context, _ string, model any, id string, update any) error {
+	r.models = append(r.models, model)
+	bar, ok := r.bars[id]
+	if !ok {
+		return domain.NewNotFoundError("entity not found")
+	}
+	if status, ok := update.(map[string]any)["status"].(string); ok {
+		bar.Status = status
+	}
+	r.bars[id] = bar
+	return nil
+}
+
+func (r *memoryBarBaseRepo) DeleteById(_ context.Context, _ string, model any, id string) error {
+	r.models = append(r.models, model)
+	if _, ok := r.bars[id]; !ok {
+		return domain.NewNotFoundError("entity not found")
+	}
+	delete(r.bars, id)
+	return nil
+}
+
+func TestRepository_CRUD(t *testing.T) {
+	base := &memoryBarBaseRepo{bars: make(map[string]domain.Bar)}
+	var repo port.Repository[domain.Bar] = repository.NewRepository[domain.Bar](base, "bar")
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &domain.Bar{Name: "A", Status: "active"})
+	require.NoError(t, err)
+	assert.Len(t, created.ID, 24, "new entities get an ObjectID hex")
+
+	// Updates return the entity as stored after the update
+	updated, err := repo.UpdateById(ctx, created.ID, map[string]any{"status": "inactive"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.Bar{ID: created.ID, Name: "A", Status: "inactive"}, *updated)
+
+	_, err = repo.UpdateById(ctx, "missing", map[string]any{"status": "inactive"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	require.NoError(t, repo.DeleteById(ctx, created.ID))
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// PostgreSQL finds the table of updates and deletes from the model
+	for _, model := range base.models {
+		assert.IsType(t, &domain.Bar{}, model)
+	}
+	assert.Len(t, base.models, 3)
+}
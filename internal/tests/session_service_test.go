@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-gin-boilerplate/internal/cache"
+	"go-gin-boilerplate/internal/domain"
+	"go-gin-boilerplate/internal/middleware"
+	"go-gin-boilerplate/internal/service"
+	"go-gin-boilerplate/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionService_ListAndRevoke(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &fakeUserRepo{users: map[string]*domain.User{
+		"1": {ID: "1", Email: "admin@example.com", PasswordHash: hash},
+		"2": {ID: "2", Email: "user@example.com", PasswordHash: hash},
+	}}
+	jwt := newTestJWT()
+	refreshStore := cache.NewMemoryRefreshTokenStore()
+	revocationStore := cache.NewMemoryRevocationStore()
+	sessionStore := cache.NewMemorySessionStore()
+	authSvc := service.NewAuthService(jwt, users, newFakeAPIKeyRepo(), refreshStore, revocationStore, sessionStore)
+	sessionSvc := service.NewSessionService(sessionStore, refreshStore, revocationStore)
+
+	router := gin.New()
+	router.GET("/protected", middleware.AuthMiddleware(jwt, revocationStore, nil), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	laptop := domain.WithClientInfo(context.Background(), domain.ClientInfo{
+		IP:        "203.0.113.7",
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 Chrome/120.0 Safari/537.36",
+		Device:    "Chrome on macOS",
+	})
+	phone := domain.WithClientInfo(context.Background(), domain.ClientInfo{IP: "198.51.100.2", Device: "Safari on iOS"})
+	login := domain.LoginRequest{Email: "admin@example.com", Password: "password123"}
+
+	first, err := authSvc.Login(laptop, login)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	second, err := authSvc.Login(phone, login)
+	require.NoError(t, err)
+	_, err = authSvc.Login(context.Background(), domain.LoginRequest{Email: "user@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	// Refreshing keeps the session
+	first, err = authSvc.RefreshToken(laptop, first.RefreshToken)
+	require.NoError(t, err)
+
+	claims, err := jwt.ValidateAccessToken(first.AccessToken)
+	require.NoError(t, err)
+	ctx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", SessionID: claims.FamilyID})
+
+	sessions, err := sessionSvc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, claims.FamilyID, sessions[0].ID, "most recently used first")
+	assert.True(t, sessions[0].Current)
+	assert.Equal(t, "Chrome on macOS", sessions[0].Device)
+	assert.Equal(t, "203.0.113.7", sessions[0].IP)
+	assert.True(t, sessions[0].LastUsedAt.After(sessions[0].CreatedAt))
+	assert.Equal(t, "Safari on iOS", sessions[1].Device)
+	assert.False(t, sessions[1].Current)
+
+	// Other users' sessions are invisible
+	otherCtx := domain.WithIdentity(context.Background(), &domain.Identity{UserID: "2"})
+	assert.ErrorIs(t, sessionSvc.Revoke(otherCtx, sessions[1].ID), domain.ErrSessionNotFound)
+
+	assert.Equal(t, http.StatusOK, performAuthRequest(router, second.AccessToken).Code)
+	require.NoError(t, sessionSvc.Revoke(ctx, sessions[1].ID))
+	assert.Equal(t, http.StatusUnauthorized, performAuthRequest(router, second.AccessToken).Code)
+	_, err = authSvc.RefreshToken(phone, second.RefreshToken)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusOK, performAuthRequest(router, first.AccessToken).Code)
+
+	sessions, err = sessionSvc.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	// Delegated credentials cannot manage sessions
+	_, err = sessionSvc.List(domain.WithIdentity(context.Background(), &domain.Identity{UserID: "1", Scopes: []string{}}))
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestDeviceName(t *testing.T) {
+	assert.Equal(t, "Safari on iOS", utils.DeviceName("Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Version/17.0 Mobile/15E148 Safari/604.1"))
+	assert.Equal(t, "Chrome on Android", utils.DeviceName("Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36"))
+	assert.Equal(t, "Edge on Windows", utils.DeviceName("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36 Edg/120.0"))
+	assert.Equal(t, "Unknown device", utils.DeviceName(""))
+}
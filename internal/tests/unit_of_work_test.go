@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-gin-boilerplate/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnitOfWork_BackendParity checks that repository calls made in a unit of
+// work are committed together or rolled back together on every backend
+func TestUnitOfWork_BackendParity(t *testing.T) {
+	for name, open := range parityBackends {
+		t.Run(name, func(t *testing.T) {
+			backend := open(t)
+			if !backend.transactional {
+				t.Skip("MongoDB is not a replica set")
+			}
+			repo := backend.repo
+			ctx := context.Background()
+
+			failed := errors.New("second write failed")
+			err := backend.unitOfWork.Do(ctx, func(ctx context.Context) error {
+				require.NoError(t, repo.Create(ctx, "bar", &domain.Bar{ID: "1", Name: "A"}))
+				require.NoError(t, repo.UpdateById(ctx, "bar", &domain.Bar{}, "1", map[string]any{"owner_id": "alice"}))
+				return failed
+			})
+			assert.ErrorIs(t, err, failed)
+			assert.ErrorIs(t, repo.GetById(ctx, "bar", "1", &domain.Bar{}), domain.ErrNotFound)
+
+			err = backend.unitOfWork.Do(ctx, func(ctx context.Context) error {
+				if err := repo.Create(ctx, "bar", &domain.Bar{ID: "1", Name: "A"}); err != nil {
+					return err
+				}
+				// A nested unit of work commits with the enclosing one
+				return backend.unitOfWork.Do(ctx, func(ctx context.Context) error {
+					return repo.Create(ctx, "bar", &domain.Bar{ID: "2", Name: "B"})
+				})
+			})
+			require.NoError(t, err)
+			var bars []*domain.Bar
+			require.NoError(t, repo.GetAll(ctx, "bar", &bars, nil))
+			assert.Len(t, bars, 2)
+		})
+	}
+}
@@ -1,74 +1,313 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"go-gin-boilerplate/config"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// TokenType distinguishes access tokens from refresh tokens
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+	// TokenTypeMFA marks the challenge token exchanged for real tokens once
+	// the second factor is verified
+	TokenTypeMFA TokenType = "mfa"
+	// TokenTypePasswordReset and TokenTypeEmailVerification are mailed to the
+	// user and redeemed once
+	TokenTypePasswordReset     TokenType = "password_reset"
+	TokenTypeEmailVerification TokenType = "email_verification"
+)
+
+// Lifetimes of the single-purpose tokens
+const (
+	// MFATokenDuration is how long a user has to complete the second login step
+	MFATokenDuration               = 5 * time.Minute
+	PasswordResetTokenDuration     = 30 * time.Minute
+	EmailVerificationTokenDuration = 24 * time.Hour
+	// ImpersonationTokenDuration bounds how long an administrator can act as
+	// another user with one token
+	ImpersonationTokenDuration = 15 * time.Minute
+)
+
 type JWT struct {
-	Secret          string
+	keys            atomic.Pointer[keySet]
 	AccessDuration  string
 	RefreshDuration string
+	Issuer          string
+	Audience        string
+}
+
+// Claims are the claims carried by every token issued by this service.
+// Refresh tokens additionally belong to a family that is shared by all
+// tokens obtained from the same login through rotation. Tokens issued to
+// OAuth2 clients carry the client ID and the space-separated granted scope.
+// Impersonation tokens name the real caller in the act claim (RFC 8693).
+type Claims struct {
+	TokenType TokenType    `json:"typ"`
+	Roles     []string     `json:"roles,omitempty"`
+	FamilyID  string       `json:"fid,omitempty"`
+	ClientID  string       `json:"client_id,omitempty"`
+	Scope     string       `json:"scope,omitempty"`
+	Actor     *ActorClaims `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ActorClaims identifies the party acting on behalf of the token's subject
+type ActorClaims struct {
+	Subject string `json:"sub"`
 }
 
-func NewJWT(config *config.JWTConfig) *JWT {
-	return &JWT{
-		Secret:          config.Secret,
+// NewJWT loads the signing keys for the configured algorithm. HMAC algorithms
+// use the shared secret; RSA, ECDSA and EdDSA use a PEM private key.
+func NewJWT(config *config.JWTConfig) (*JWT, error) {
+	j := &JWT{
 		AccessDuration:  config.AccessDuration.String(),
 		RefreshDuration: config.RefreshDuration.String(),
+		Issuer:          config.Issuer,
+		Audience:        config.Audience,
 	}
+	if err := j.ReloadKeys(config); err != nil {
+		return nil, err
+	}
+	return j, nil
 }
 
-func (j *JWT) GenerateAccessToken(userID string) (string, error) {
-	accessDuration, err := time.ParseDuration(j.AccessDuration)
+// ReloadKeys atomically replaces the key set, e.g. after rotating keys in the
+// config file. On error the current keys stay in use.
+func (j *JWT) ReloadKeys(config *config.JWTConfig) error {
+	keys, err := loadKeySet(config)
 	if err != nil {
-		return "", err
+		return err
 	}
+	j.keys.Store(keys)
+	return nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(accessDuration).Unix(),
-	})
+// GenerateAccessToken issues an access token carrying the user's roles, with
+// its own jti so it can be revoked individually before it expires
+func (j *JWT) GenerateAccessToken(userID string, roles []string) (string, error) {
+	return j.GenerateSessionAccessToken(userID, roles, "")
+}
 
-	accessToken, err := token.SignedString([]byte(j.Secret))
+// GenerateSessionAccessToken issues an access token that also names the
+// refresh token family (login session) it was issued with, so that revoking
+// the session revokes the access token too
+func (j *JWT) GenerateSessionAccessToken(userID string, roles []string, familyID string) (string, error) {
+	claims, err := j.newClaims(userID, TokenTypeAccess, j.AccessDuration)
 	if err != nil {
 		return "", err
 	}
+	claims.Roles = roles
+	claims.FamilyID = familyID
 
-	return accessToken, nil
+	return j.sign(claims)
 }
 
-func (j *JWT) GenerateRefreshToken(userID string) (string, error) {
-	refreshDuration, err := time.ParseDuration(j.RefreshDuration)
+// GenerateClientAccessToken issues an access token to an OAuth2 client limited
+// to the granted scopes. With a userID the client acts on behalf of that user;
+// without one (client_credentials) the client itself is the subject.
+func (j *JWT) GenerateClientAccessToken(clientID, userID string, roles, scopes []string) (string, *Claims, error) {
+	subject := userID
+	if subject == "" {
+		subject = clientID
+	}
+	claims, err := j.newClaims(subject, TokenTypeAccess, j.AccessDuration)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
+	claims.Roles = roles
+	claims.ClientID = clientID
+	claims.Scope = strings.Join(scopes, " ")
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(refreshDuration).Unix(),
-	})
+	token, err := j.sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+// GenerateImpersonationToken issues a short-lived access token for userID
+// that records actorID, the administrator using it, in the act claim. It
+// has no refresh token and cannot be extended.
+func (j *JWT) GenerateImpersonationToken(userID string, roles []string, actorID string) (string, *Claims, error) {
+	claims, err := j.newClaims(userID, TokenTypeAccess, ImpersonationTokenDuration.String())
+	if err != nil {
+		return "", nil, err
+	}
+	claims.Roles = roles
+	claims.Actor = &ActorClaims{Subject: actorID}
+
+	token, err := j.sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
 
-	refreshToken, err := token.SignedString([]byte(j.Secret))
+// GenerateMFAToken issues a short-lived challenge token for a user who passed
+// the password check but still has to provide a second factor
+func (j *JWT) GenerateMFAToken(userID string) (string, error) {
+	return j.GeneratePurposeToken(userID, TokenTypeMFA, MFATokenDuration)
+}
+
+// GeneratePurposeToken issues a token that is only accepted by the flow of
+// the given type, e.g. a password reset link. Callers revoke its jti once used.
+func (j *JWT) GeneratePurposeToken(userID string, tokenType TokenType, lifetime time.Duration) (string, error) {
+	claims, err := j.newClaims(userID, tokenType, lifetime.String())
 	if err != nil {
 		return "", err
 	}
+	return j.sign(claims)
+}
+
+// GenerateRefreshToken issues a refresh token with a fresh jti in the given
+// family. An empty familyID starts a new family.
+func (j *JWT) GenerateRefreshToken(userID, familyID string) (string, *Claims, error) {
+	claims, err := j.newClaims(userID, TokenTypeRefresh, j.RefreshDuration)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if familyID == "" {
+		familyID, err = RandomID(16)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	claims.FamilyID = familyID
 
-	return refreshToken, nil
+	refreshToken, err := j.sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return refreshToken, claims, nil
 }
 
-func (j *JWT) ValidateToken(token string) (string, error) {
-	claims := jwt.MapClaims{}
+// ValidateToken verifies the signature, lifetime, issuer and audience of the
+// token and that it is of the expected type
+func (j *JWT) ValidateToken(token string, expected TokenType) (*Claims, error) {
+	claims := &Claims{}
+	keys := j.keys.Load()
+
+	opts := []jwt.ParserOption{
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if j.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(j.Issuer))
+	}
+	if j.Audience != "" {
+		opts = append(opts, jwt.WithAudience(j.Audience))
+	}
 
 	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
-		return []byte(j.Secret), nil
-	})
+		// Tokens issued before kid headers were introduced use the active key
+		key := keys.active
+		if kid, ok := token.Header["kid"].(string); ok {
+			if key, ok = keys.byKid[kid]; !ok {
+				return nil, fmt.Errorf("unknown key id %q", kid)
+			}
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return key.public, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != expected {
+		return nil, errors.New("unexpected token type")
+	}
+	if claims.ID == "" || claims.Subject == "" || claims.IssuedAt == nil {
+		return nil, errors.New("malformed token")
+	}
+	if expected == TokenTypeRefresh && claims.FamilyID == "" {
+		return nil, errors.New("malformed token")
+	}
+
+	return claims, nil
+}
+
+// ValidateAccessToken validates a token that must be an access token
+func (j *JWT) ValidateAccessToken(token string) (*Claims, error) {
+	return j.ValidateToken(token, TokenTypeAccess)
+}
 
+// ValidateRefreshToken validates a token that must be a refresh token
+func (j *JWT) ValidateRefreshToken(token string) (*Claims, error) {
+	return j.ValidateToken(token, TokenTypeRefresh)
+}
+
+// ValidateMFAToken validates a token that must be an MFA challenge token
+func (j *JWT) ValidateMFAToken(token string) (*Claims, error) {
+	return j.ValidateToken(token, TokenTypeMFA)
+}
+
+// newClaims builds the common claim set for a token of the given type
+func (j *JWT) newClaims(userID string, tokenType TokenType, duration string) (*Claims, error) {
+	lifetime, err := time.ParseDuration(duration)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	jti, err := RandomID(16)
+	if err != nil {
+		return nil, err
 	}
 
-	return claims["sub"].(string), nil
+	now := time.Now()
+	claims := &Claims{
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   userID,
+			Issuer:    j.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
+		},
+	}
+	if j.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{j.Audience}
+	}
+
+	return claims, nil
+}
+
+// JWKS returns the public verification keys, including verification-only
+// keys kept around during rotation. HMAC secrets are never published.
+func (j *JWT) JWKS() JSONWebKeySet {
+	keys := j.keys.Load()
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range keys.byKid {
+		if key.symmetric {
+			continue
+		}
+		if jwk, ok := newJSONWebKey(key.public, key.method.Alg()); ok {
+			jwk.Kid = key.kid
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	sort.Slice(set.Keys, func(a, b int) bool { return set.Keys[a].Kid < set.Keys[b].Kid })
+	return set
+}
+
+// sign signs the claims with the active key and stamps its kid header
+func (j *JWT) sign(claims *Claims) (string, error) {
+	key := j.keys.Load().active
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.private)
 }
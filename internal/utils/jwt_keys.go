@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-gin-boilerplate/config"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey holds a key identified by kid together with its signing method.
+// For HMAC both private and public are the shared secret; verification-only
+// keys have no private part.
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	private   any
+	public    crypto.PublicKey
+	symmetric bool
+}
+
+// keySet is an immutable set of keys with the one used for signing new tokens
+type keySet struct {
+	active *signingKey
+	byKid  map[string]*signingKey
+}
+
+// loadKeySet builds the key set from config. When no key list is configured
+// the top-level algorithm/secret/private key form a single active key.
+func loadKeySet(jwtConfig *config.JWTConfig) (*keySet, error) {
+	keyConfigs := jwtConfig.Keys
+	activeKid := jwtConfig.ActiveKid
+	if len(keyConfigs) == 0 {
+		keyConfigs = []config.JWTKeyConfig{{
+			Algorithm:      jwtConfig.Algorithm,
+			Secret:         jwtConfig.Secret,
+			PrivateKey:     jwtConfig.PrivateKey,
+			PrivateKeyFile: jwtConfig.PrivateKeyFile,
+		}}
+		activeKid = ""
+	}
+
+	set := &keySet{byKid: make(map[string]*signingKey, len(keyConfigs))}
+	for i := range keyConfigs {
+		key, err := loadSigningKey(&keyConfigs[i])
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := set.byKid[key.kid]; exists {
+			return nil, fmt.Errorf("duplicate JWT key id: %s", key.kid)
+		}
+		set.byKid[key.kid] = key
+
+		// Without an explicit active kid the first key signs
+		if (activeKid == "" && i == 0) || key.kid == activeKid {
+			set.active = key
+		}
+	}
+
+	if set.active == nil {
+		return nil, fmt.Errorf("active JWT key %q is not configured", activeKid)
+	}
+	if set.active.private == nil {
+		return nil, fmt.Errorf("active JWT key %q has no private key", set.active.kid)
+	}
+
+	return set, nil
+}
+
+// loadSigningKey resolves the configured algorithm and loads the matching key
+func loadSigningKey(config *config.JWTKeyConfig) (*signingKey, error) {
+	alg := config.Algorithm
+	if alg == "" {
+		alg = jwt.SigningMethodHS256.Alg()
+	}
+
+	method := jwt.GetSigningMethod(alg)
+	if method == nil || method == jwt.SigningMethodNone {
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", alg)
+	}
+
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		if config.Secret == "" {
+			return nil, fmt.Errorf("JWT secret is required for %s", alg)
+		}
+		secret := []byte(config.Secret)
+		key := &signingKey{kid: config.Kid, method: method, private: secret, public: secret, symmetric: true}
+		if key.kid == "" {
+			sum := sha256.Sum256(secret)
+			key.kid = hex.EncodeToString(sum[:8])
+		}
+		return key, nil
+	}
+
+	key := &signingKey{kid: config.Kid, method: method}
+	var err error
+	if config.PrivateKey != "" || config.PrivateKeyFile != "" {
+		err = key.loadPrivate(config)
+	} else {
+		err = key.loadPublic(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if key.kid == "" {
+		key.kid, err = thumbprint(key.public)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+func (k *signingKey) loadPrivate(config *config.JWTKeyConfig) error {
+	pemBytes, err := readPEM(config.PrivateKey, config.PrivateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	switch method := k.method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		k.private, k.public = private, &private.PublicKey
+	case *jwt.SigningMethodECDSA:
+		private, err := jwt.ParseECPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse EC private key: %w", err)
+		}
+		if private.Curve.Params().BitSize != method.CurveBits {
+			return fmt.Errorf("EC key curve does not match %s", method.Alg())
+		}
+		k.private, k.public = private, &private.PublicKey
+	case *jwt.SigningMethodEd25519:
+		private, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse Ed25519 private key: %w", err)
+		}
+		edKey, ok := private.(ed25519.PrivateKey)
+		if !ok {
+			return fmt.Errorf("key is not an Ed25519 private key")
+		}
+		k.private, k.public = edKey, edKey.Public()
+	default:
+		return fmt.Errorf("unsupported JWT algorithm: %s", k.method.Alg())
+	}
+
+	return nil
+}
+
+func (k *signingKey) loadPublic(config *config.JWTKeyConfig) error {
+	pemBytes, err := readPEM(config.PublicKey, config.PublicKeyFile)
+	if err != nil {
+		return err
+	}
+
+	switch method := k.method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		k.public, err = jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+	case *jwt.SigningMethodECDSA:
+		public, parseErr := jwt.ParseECPublicKeyFromPEM(pemBytes)
+		if parseErr == nil && public.Curve.Params().BitSize != method.CurveBits {
+			parseErr = fmt.Errorf("EC key curve does not match %s", method.Alg())
+		}
+		k.public, err = public, parseErr
+	case *jwt.SigningMethodEd25519:
+		k.public, err = jwt.ParseEdPublicKeyFromPEM(pemBytes)
+	default:
+		return fmt.Errorf("unsupported JWT algorithm: %s", k.method.Alg())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return nil
+}
+
+// readPEM returns the inline PEM or reads it from the given file
+func readPEM(inline, file string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if file == "" {
+		return nil, fmt.Errorf("JWT key is required for asymmetric algorithms")
+	}
+	pemBytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT key file: %w", err)
+	}
+	return pemBytes, nil
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint used as default key id
+func thumbprint(key crypto.PublicKey) (string, error) {
+	jwk, ok := newJSONWebKey(key, "")
+	if !ok {
+		return "", fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	// Required members only, in lexicographic order
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "golang.org/x/crypto/bcrypt"
+
+// MaxPasswordBytes is the longest password bcrypt can hash
+const MaxPasswordBytes = 72
+
+// ErrPasswordTooLong is returned by HashPassword for passwords longer than
+// MaxPasswordBytes
+var ErrPasswordTooLong = bcrypt.ErrPasswordTooLong
+
+// HashPassword returns the bcrypt hash of the given plaintext password
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether the plaintext password matches the bcrypt hash
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,47 @@
+package utils
+
+import "strings"
+
+// DeviceName returns a short description such as "Chrome on macOS" for a
+// User-Agent header. Unknown parts are left out; an empty or unrecognised
+// header yields "Unknown device".
+func DeviceName(userAgent string) string {
+	browser := firstMatch(userAgent, [][2]string{
+		// Order matters: most browsers also claim to be Safari or Chrome
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	})
+	os := firstMatch(userAgent, [][2]string{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Mac OS X", "macOS"},
+		{"Windows", "Windows"},
+		{"Linux", "Linux"},
+	})
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "Unknown device"
+	}
+}
+
+// firstMatch returns the name paired with the first marker found in s
+func firstMatch(s string, markers [][2]string) string {
+	for _, marker := range markers {
+		if strings.Contains(s, marker[0]) {
+			return marker[1]
+		}
+	}
+	return ""
+}